@@ -0,0 +1,117 @@
+package future
+
+import (
+	"sync/atomic"
+
+	"github.com/WhiCu/async/try"
+)
+
+// chain returns a future that is completed with the result of fn,
+// run in a new goroutine once f completes. Cancelling it cancels f
+// and awaiting it starts f.
+func chain[T, U any](f *Future[T], fn func() (U, error)) *Future[U] {
+	out := newFuture[U]()
+	out.cancel = f.Cancel
+	out.start = f.await
+	f.then(func() {
+		out.run(fn)
+	})
+	return out
+}
+
+// Map returns a future holding fn applied to the value of f.
+// If f fails, the returned future holds the same error and fn is not called.
+// A panic in fn is converted to error.
+func Map[T, U any](f *Future[T], fn func(T) U) *Future[U] {
+	return chain(f, func() (U, error) {
+		if f.err != nil {
+			var zero U
+			return zero, f.err
+		}
+		return try.TryValue(func() U {
+			return fn(f.value)
+		})
+	})
+}
+
+// Then is like Map but fn may fail.
+func Then[T, U any](f *Future[T], fn func(T) (U, error)) *Future[U] {
+	return chain(f, func() (U, error) {
+		if f.err != nil {
+			var zero U
+			return zero, f.err
+		}
+		return try.TryValueErr(func() (U, error) {
+			return fn(f.value)
+		})
+	})
+}
+
+// FlatMap is like Map but fn starts another future,
+// and the returned future completes with the result of that one.
+// Cancelling the returned future cancels both f and the future started by fn.
+func FlatMap[T, U any](f *Future[T], fn func(T) *Future[U]) *Future[U] {
+	out := newFuture[U]()
+	out.start = f.await
+
+	var inner atomic.Pointer[Future[U]]
+	var cancelled atomic.Bool
+	out.cancel = func() {
+		cancelled.Store(true)
+		f.Cancel()
+		if next := inner.Load(); next != nil {
+			next.Cancel()
+		}
+	}
+
+	f.then(func() {
+		var zero U
+		if f.err != nil {
+			out.complete(zero, f.err)
+			return
+		}
+
+		go func() {
+			err := try.Try(func() {
+				next := fn(f.value)
+				if next == nil {
+					out.complete(zero, ErrNilFuture)
+					return
+				}
+				inner.Store(next)
+				if cancelled.Load() {
+					next.Cancel()
+				}
+				next.await()
+				next.then(func() {
+					out.complete(next.value, next.err)
+				})
+			})
+			if err != nil {
+				out.complete(zero, err)
+			}
+		}()
+	})
+	return out
+}
+
+// Recover returns a future holding the value of f or,
+// if f fails, the result of fn called with its error.
+// A panic in f reaches fn as a *try.PanicError.
+func Recover[T any](f *Future[T], fn func(error) (T, error)) *Future[T] {
+	return chain(f, func() (T, error) {
+		if f.err == nil {
+			return f.value, nil
+		}
+		return try.TryValueErr(func() (T, error) {
+			return fn(f.err)
+		})
+	})
+}
+
+// OrElse returns a future holding the value of f, or value if f fails.
+func OrElse[T any](f *Future[T], value T) *Future[T] {
+	return Recover(f, func(error) (T, error) {
+		return value, nil
+	})
+}
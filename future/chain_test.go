@@ -0,0 +1,126 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/WhiCu/async/try"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChain(t *testing.T) {
+	testErr := errors.New("fail")
+
+	Convey("Given Map", t, func() {
+		Convey("When the source succeeds", func() {
+			v, err := Map(Promise(func() int { return 21 }), func(v int) int { return v * 2 }).Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 42)
+		})
+
+		Convey("When the source fails", func() {
+			called := false
+			_, err := Map(after(0, 0, testErr), func(v int) int {
+				called = true
+				return v
+			}).Value()
+			So(err, ShouldEqual, testErr)
+			So(called, ShouldBeFalse)
+		})
+
+		Convey("When the function panics", func() {
+			_, err := Map(Promise(func() int { return 1 }), func(int) int { panic("boom") }).Value()
+			So(try.AsPanicError(err), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given Then", t, func() {
+		Convey("When the steps succeed", func() {
+			f := Then(Promise(func() string { return "12" }), strconv.Atoi)
+			v, err := Then(f, func(v int) (int, error) { return v + 1, nil }).Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 13)
+		})
+
+		Convey("When a step fails", func() {
+			_, err := Then(Promise(func() string { return "x" }), strconv.Atoi).Value()
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Given FlatMap", t, func() {
+		Convey("When the inner future succeeds", func() {
+			v, err := FlatMap(Promise(func() int { return 2 }), func(v int) *Future[int] {
+				return Promise(func() int { return v * 10 })
+			}).Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 20)
+		})
+
+		Convey("When the inner future fails", func() {
+			_, err := FlatMap(Promise(func() int { return 2 }), func(int) *Future[int] {
+				return after(0, 0, testErr)
+			}).Value()
+			So(err, ShouldEqual, testErr)
+		})
+
+		Convey("When fn returns a nil future", func() {
+			_, err := FlatMap(Promise(func() int { return 2 }), func(int) *Future[int] {
+				return nil
+			}).Value()
+			So(err, ShouldEqual, ErrNilFuture)
+		})
+
+		Convey("When it is cancelled", func() {
+			cancelled := make(chan error, 1)
+			f := FlatMap(Promise(func() int { return 1 }), func(int) *Future[int] {
+				return blocking(cancelled)
+			})
+			f.Cancel()
+
+			Convey("Then the inner future should be cancelled", func() {
+				So(<-cancelled, ShouldEqual, ErrCanceled)
+				So(f.Err(), ShouldEqual, context.Canceled)
+			})
+		})
+	})
+
+	Convey("Given Recover and OrElse", t, func() {
+		Convey("When the source succeeds", func() {
+			v, err := OrElse(Promise(func() int { return 1 }), -1).Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 1)
+		})
+
+		Convey("When the source panics", func() {
+			var got error
+			v, err := Recover(Promise(func() int { panic("boom") }), func(err error) (int, error) {
+				got = err
+				return -1, nil
+			}).Value()
+
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, -1)
+			So(try.AsPanicError(got), ShouldBeTrue)
+		})
+
+		Convey("When the source fails", func() {
+			v, err := OrElse(after(0, 0, testErr), -1).Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, -1)
+		})
+	})
+
+	Convey("Given a chained future", t, func() {
+		cancelled := make(chan error, 1)
+		f := Map(blocking(cancelled), strconv.Itoa)
+
+		Convey("Cancel should cancel the source", func() {
+			f.Cancel()
+			So(<-cancelled, ShouldEqual, ErrCanceled)
+			So(f.Err(), ShouldEqual, context.Canceled)
+		})
+	})
+}
@@ -0,0 +1,141 @@
+package future
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Result is the outcome of a single future, as reported by AllSettled.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// cancelAll cancels every future in fs; see Future.Cancel.
+func cancelAll[T any](fs []*Future[T]) {
+	for _, f := range fs {
+		f.Cancel()
+	}
+}
+
+// combine creates a future whose Cancel cancels all of fs
+// and whose awaiting starts all of them.
+func combine[T, R any](fs []*Future[T]) *Future[R] {
+	out := newFuture[R]()
+	out.cancel = func() {
+		cancelAll(fs)
+	}
+	out.start = func() {
+		for _, f := range fs {
+			f.await()
+		}
+	}
+	return out
+}
+
+// All returns a future that completes with the values of fs, in the same order,
+// once all of them succeed, or with the first error as soon as one fails.
+// When one fails, the others are cancelled.
+func All[T any](fs ...*Future[T]) *Future[[]T] {
+	out := combine[T, []T](fs)
+	values := make([]T, len(fs))
+	if len(fs) == 0 {
+		out.complete(values, nil)
+		return out
+	}
+
+	var remaining atomic.Int64
+	remaining.Store(int64(len(fs)))
+	for i, f := range fs {
+		f.then(func() {
+			if f.err != nil {
+				if out.complete(nil, f.err) {
+					cancelAll(fs)
+				}
+				return
+			}
+			values[i] = f.value
+			if remaining.Add(-1) == 0 {
+				out.complete(values, nil)
+			}
+		})
+	}
+	return out
+}
+
+// Any returns a future that completes with the value of the first of fs to succeed.
+// The others are then cancelled. If all of them fail, the future holds
+// their errors joined with errors.Join, in the order of fs.
+// If fs is empty, the future holds ErrEmpty.
+func Any[T any](fs ...*Future[T]) *Future[T] {
+	out := combine[T, T](fs)
+	if len(fs) == 0 {
+		var zero T
+		out.complete(zero, ErrEmpty)
+		return out
+	}
+
+	errs := make([]error, len(fs))
+	var remaining atomic.Int64
+	remaining.Store(int64(len(fs)))
+	for i, f := range fs {
+		f.then(func() {
+			if f.err == nil {
+				if out.complete(f.value, nil) {
+					cancelAll(fs)
+				}
+				return
+			}
+			errs[i] = f.err
+			if remaining.Add(-1) == 0 {
+				var zero T
+				out.complete(zero, errors.Join(errs...))
+			}
+		})
+	}
+	return out
+}
+
+// Race returns a future that completes with the result of the first of fs
+// to complete, whether it succeeded or not. The others are then cancelled.
+// If fs is empty, the future holds ErrEmpty.
+func Race[T any](fs ...*Future[T]) *Future[T] {
+	out := combine[T, T](fs)
+	if len(fs) == 0 {
+		var zero T
+		out.complete(zero, ErrEmpty)
+		return out
+	}
+
+	for _, f := range fs {
+		f.then(func() {
+			if out.complete(f.value, f.err) {
+				cancelAll(fs)
+			}
+		})
+	}
+	return out
+}
+
+// AllSettled returns a future that completes once all of fs complete,
+// with the outcome of each of them in the same order. It never holds an error.
+func AllSettled[T any](fs ...*Future[T]) *Future[[]Result[T]] {
+	out := combine[T, []Result[T]](fs)
+	results := make([]Result[T], len(fs))
+	if len(fs) == 0 {
+		out.complete(results, nil)
+		return out
+	}
+
+	var remaining atomic.Int64
+	remaining.Store(int64(len(fs)))
+	for i, f := range fs {
+		f.then(func() {
+			results[i] = Result[T]{Value: f.value, Err: f.err}
+			if remaining.Add(-1) == 0 {
+				out.complete(results, nil)
+			}
+		})
+	}
+	return out
+}
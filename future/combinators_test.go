@@ -0,0 +1,135 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/WhiCu/async/try"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func after[T any](d time.Duration, v T, err error) *Future[T] {
+	return PromiseErr(func() (T, error) {
+		time.Sleep(d)
+		return v, err
+	})
+}
+
+// blocking returns a future that waits for its context and reports whether it was cancelled.
+func blocking(cancelled chan<- error) *Future[int] {
+	return PromiseCtx(context.Background(), func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		cancelled <- context.Cause(ctx)
+		return 0, ctx.Err()
+	})
+}
+
+func TestCombinators(t *testing.T) {
+	testErr := errors.New("fail")
+
+	Convey("Given All", t, func() {
+		Convey("When all futures succeed", func() {
+			vs, err := All(after(20*time.Millisecond, 1, nil), after(0, 2, nil), after(10*time.Millisecond, 3, nil)).Value()
+
+			Convey("Then it should return the values in order", func() {
+				So(err, ShouldBeNil)
+				So(vs, ShouldResemble, []int{1, 2, 3})
+			})
+		})
+
+		Convey("When one future fails", func() {
+			cancelled := make(chan error, 1)
+			_, err := All(blocking(cancelled), after(0, 0, testErr)).Value()
+
+			Convey("Then it should return the error and cancel the others", func() {
+				So(err, ShouldEqual, testErr)
+				So(<-cancelled, ShouldEqual, ErrCanceled)
+			})
+		})
+
+		Convey("When a future panics", func() {
+			_, err := All(after(0, 1, nil), Promise(func() int { panic("boom") })).Value()
+
+			Convey("Then it should return a PanicError", func() {
+				So(try.AsPanicError(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When there are no futures", func() {
+			vs, err := All[int]().Value()
+			So(err, ShouldBeNil)
+			So(vs, ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given Any", t, func() {
+		Convey("When one future succeeds after others fail", func() {
+			cancelled := make(chan error, 1)
+			v, err := Any(after(0, 0, testErr), after(10*time.Millisecond, 7, nil), blocking(cancelled)).Value()
+
+			Convey("Then it should return the first success and cancel the rest", func() {
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, 7)
+				So(<-cancelled, ShouldEqual, ErrCanceled)
+			})
+		})
+
+		Convey("When all futures fail", func() {
+			_, err := Any(after(0, 0, testErr), Promise(func() int { panic("boom") })).Value()
+
+			Convey("Then it should return all the errors", func() {
+				So(errors.Is(err, testErr), ShouldBeTrue)
+				So(try.AsPanicError(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When there are no futures", func() {
+			_, err := Any[int]().Value()
+			So(err, ShouldEqual, ErrEmpty)
+		})
+	})
+
+	Convey("Given Race", t, func() {
+		Convey("When the first future to complete fails", func() {
+			cancelled := make(chan error, 1)
+			_, err := Race(after(0, 0, testErr), blocking(cancelled)).Value()
+
+			Convey("Then it should return its error and cancel the rest", func() {
+				So(err, ShouldEqual, testErr)
+				So(<-cancelled, ShouldEqual, ErrCanceled)
+			})
+		})
+
+		Convey("When the first future to complete succeeds", func() {
+			v, err := Race(after(50*time.Millisecond, 1, nil), after(0, 2, nil)).Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given AllSettled", t, func() {
+		rs, err := AllSettled(after(0, 1, nil), after(0, 0, testErr), Promise(func() int { panic("boom") })).Value()
+
+		Convey("Then it should return every outcome in order", func() {
+			So(err, ShouldBeNil)
+			So(rs, ShouldHaveLength, 3)
+			So(rs[0], ShouldResemble, Result[int]{Value: 1})
+			So(rs[1].Err, ShouldEqual, testErr)
+			So(try.AsPanicError(rs[2].Err), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a combined future", t, func() {
+		cancelled := make(chan error, 2)
+		all := All(blocking(cancelled), blocking(cancelled))
+
+		Convey("Cancel should cancel the futures it was built from", func() {
+			all.Cancel()
+			So(<-cancelled, ShouldEqual, ErrCanceled)
+			So(<-cancelled, ShouldEqual, ErrCanceled)
+			So(all.Err(), ShouldEqual, context.Canceled)
+		})
+	})
+}
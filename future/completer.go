@@ -0,0 +1,32 @@
+package future
+
+// Completer resolves a Future from code that does not own the goroutine
+// computing its value, such as callbacks or RPC replies.
+type Completer[T any] struct {
+	future *Future[T]
+}
+
+// NewCompleter creates a Completer with a pending Future.
+func NewCompleter[T any]() *Completer[T] {
+	return &Completer[T]{
+		future: newFuture[T](),
+	}
+}
+
+// Future returns the future controlled by the Completer.
+func (c *Completer[T]) Future() *Future[T] {
+	return c.future
+}
+
+// Resolve completes the future with value.
+// It reports false if the future has already been completed.
+func (c *Completer[T]) Resolve(value T) bool {
+	return c.future.complete(value, nil)
+}
+
+// Reject completes the future with err.
+// It reports false if the future has already been completed.
+func (c *Completer[T]) Reject(err error) bool {
+	var zero T
+	return c.future.complete(zero, err)
+}
@@ -0,0 +1,67 @@
+package future
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCompleter(t *testing.T) {
+	Convey("Given a new Completer", t, func() {
+		c := NewCompleter[int]()
+		fut := c.Future()
+
+		Convey("The future should be pending", func() {
+			_, _, ok := fut.Poll()
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("When it is resolved", func() {
+			So(c.Resolve(10), ShouldBeTrue)
+
+			Convey("Then the future should hold the value", func() {
+				v, err := fut.Value()
+				So(v, ShouldEqual, 10)
+				So(err, ShouldBeNil)
+				So(fut.Err(), ShouldBeNil)
+			})
+
+			Convey("And later completions should be ignored", func() {
+				So(c.Resolve(20), ShouldBeFalse)
+				So(c.Reject(errors.New("late")), ShouldBeFalse)
+
+				v, err := fut.Value()
+				So(v, ShouldEqual, 10)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When it is rejected", func() {
+			testErr := errors.New("rejected")
+			So(c.Reject(testErr), ShouldBeTrue)
+
+			Convey("Then the future should hold the error", func() {
+				v, err := fut.Value()
+				So(v, ShouldEqual, 0)
+				So(err, ShouldEqual, testErr)
+				So(fut.Err(), ShouldEqual, testErr)
+			})
+		})
+
+		Convey("When it is resolved from another goroutine", func() {
+			futures := []*Future[int]{fut, Promise(func() int { return 2 })}
+			go c.Resolve(1)
+
+			Convey("Then it can be awaited like any other future", func() {
+				sum := 0
+				for _, f := range futures {
+					v, err := f.Value()
+					So(err, ShouldBeNil)
+					sum += v
+				}
+				So(sum, ShouldEqual, 3)
+			})
+		})
+	})
+}
@@ -0,0 +1,11 @@
+package future
+
+import (
+	"errors"
+)
+
+var (
+	ErrCanceled  = errors.New("future: canceled")
+	ErrEmpty     = errors.New("future: no futures")
+	ErrNilFuture = errors.New("future: FlatMap function returned a nil future")
+)
@@ -2,59 +2,179 @@
 // It allows running functions in goroutines and converts any panics to errors for easier handling.
 package future
 
-import "github.com/WhiCu/async/try"
+import (
+	"context"
+	"sync"
 
-// future represents an asynchronous computation that converts panics to errors.
-// It wraps a channel for receiving results and uses Trier for panic handling.
-type future[T any] struct {
-	value <-chan valueError[T]
-}
+	"github.com/WhiCu/async/try"
+)
+
+// Future represents an asynchronous computation that converts panics to errors.
+// Its result is memoized, so it can be read any number of times from any number of goroutines.
+// The zero Future is not usable; futures are created by Promise and its variants or by a Completer.
+type Future[T any] struct {
+	mu        sync.Mutex
+	done      chan struct{}
+	completed bool
+	callbacks []func()
 
-// valueError wraps a value and error result from a future computation.
-type valueError[T any] struct {
 	value T
 	err   error
+
+	// cancel, if set, cancels the computation behind the future.
+	cancel func()
+	// start, if set, starts the computation of a lazy future.
+	// It may be called any number of times.
+	start func()
 }
 
-// Promise creates a future that executes function f asynchronously.
-// It returns a future that will contain the result or an error if the function panicked.
-func Promise[T any](f func() T) *future[T] {
-	c := make(chan valueError[T])
-	future := &future[T]{
-		value: c,
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{
+		done: make(chan struct{}),
 	}
+}
+
+// run executes fn in a new goroutine and completes the future with its result,
+// or with try.ErrGoexit if fn calls runtime.Goexit.
+func (f *Future[T]) run(fn func() (T, error)) *Future[T] {
 	go func() {
-		defer close(c)
-		v, err := try.TryValue(f)
-		c <- valueError[T]{
-			value: v,
-			err:   err,
-		}
+		var value T
+		try.Run(func() (err error) {
+			value, err = fn()
+			return err
+		}, func(err error) {
+			f.complete(value, err)
+		})
 	}()
-	return future
+	return f
+}
+
+// complete stores the result, wakes up all waiters and runs the callbacks.
+// Only the first call has an effect; it reports whether this call completed the future.
+func (f *Future[T]) complete(value T, err error) bool {
+	f.mu.Lock()
+	if f.completed {
+		f.mu.Unlock()
+		return false
+	}
+	f.completed = true
+	f.value, f.err = value, err
+	callbacks := f.callbacks
+	f.callbacks = nil
+	close(f.done)
+	f.mu.Unlock()
+
+	for _, cb := range callbacks {
+		cb()
+	}
+	return true
+}
+
+// await starts the computation of a lazy future.
+func (f *Future[T]) await() {
+	if f.start != nil {
+		f.start()
+	}
+}
+
+// then calls cb once the future completes, on the goroutine that completes it,
+// or immediately if it already has. cb must not block.
+func (f *Future[T]) then(cb func()) {
+	f.mu.Lock()
+	if !f.completed {
+		f.callbacks = append(f.callbacks, cb)
+		f.mu.Unlock()
+		return
+	}
+	f.mu.Unlock()
+	cb()
+}
+
+// Promise creates a future that executes function f asynchronously.
+// It returns a future that will contain the result or an error if the function panicked.
+func Promise[T any](f func() T) *Future[T] {
+	return newFuture[T]().run(func() (T, error) {
+		return try.TryValue(f)
+	})
 }
 
 // PromiseErr creates a future that executes function f asynchronously and handles errors.
 // It returns a future that will contain the result, error, or panic converted to error.
-func PromiseErr[T any](f func() (T, error)) *future[T] {
-	c := make(chan valueError[T])
-	future := &future[T]{
-		value: c,
+func PromiseErr[T any](f func() (T, error)) *Future[T] {
+	return newFuture[T]().run(func() (T, error) {
+		return try.TryValueErr(f)
+	})
+}
+
+// PromiseCtx creates a future that executes function f asynchronously with a context derived from ctx.
+// f is expected to return once its context is done; the future then holds whatever f returned,
+// or a panic converted to error. The context is also cancelled by Cancel.
+func PromiseCtx[T any](ctx context.Context, f func(context.Context) (T, error)) *Future[T] {
+	ctx, cancel := context.WithCancelCause(ctx)
+	future := newFuture[T]()
+	future.cancel = func() {
+		cancel(ErrCanceled)
 	}
-	go func() {
-		defer close(c)
-		v, err := try.TryValueErr(f)
-		c <- valueError[T]{
-			value: v,
-			err:   err,
-		}
-	}()
-	return future
+	return future.run(func() (T, error) {
+		defer cancel(nil)
+		return try.TryValueErr(func() (T, error) {
+			return f(ctx)
+		})
+	})
 }
 
 // Value blocks until the future computation completes and returns the result.
 // It returns the computed value and any error (including panics converted to errors).
-func (f *future[T]) Value() (value T, err error) {
-	r := <-f.value
-	return r.value, r.err
+// Every call returns the same result.
+func (f *Future[T]) Value() (value T, err error) {
+	f.await()
+	<-f.done
+	return f.value, f.err
+}
+
+// ValueCtx is like Value but stops waiting once ctx is done.
+// In that case it returns the zero value and the cause of ctx.
+func (f *Future[T]) ValueCtx(ctx context.Context) (value T, err error) {
+	f.await()
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		return value, context.Cause(ctx)
+	}
+}
+
+// Err blocks until the future completes and returns its error.
+func (f *Future[T]) Err() error {
+	f.await()
+	<-f.done
+	return f.err
+}
+
+// Done returns a channel that is closed when the future completes.
+// It starts a lazy future.
+func (f *Future[T]) Done() <-chan struct{} {
+	f.await()
+	return f.done
+}
+
+// Poll returns the result without blocking.
+// ok is false if the future has not completed yet.
+// Unlike the other accessors, it does not start a lazy future.
+func (f *Future[T]) Poll() (value T, err error, ok bool) {
+	select {
+	case <-f.done:
+		return f.value, f.err, true
+	default:
+		return value, nil, false
+	}
+}
+
+// Cancel cancels the context of a future created with PromiseCtx,
+// or of the futures a combined future was built from.
+// It has no effect on other futures or on a completed future.
+func (f *Future[T]) Cancel() {
+	if f.cancel != nil {
+		f.cancel()
+	}
 }
@@ -1,9 +1,14 @@
 package future
 
 import (
+	"context"
 	"errors"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/WhiCu/async/try"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -73,3 +78,155 @@ func TestFuture(t *testing.T) {
 		})
 	})
 }
+
+func TestFutureCtx(t *testing.T) {
+	Convey("Given a PromiseCtx", t, func() {
+		Convey("When the function returns before the context is done", func() {
+			fut := PromiseCtx(context.Background(), func(ctx context.Context) (int, error) {
+				return 5, nil
+			})
+			val, err := fut.ValueCtx(context.Background())
+
+			Convey("Then it should return the value", func() {
+				So(val, ShouldEqual, 5)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When its context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			fut := PromiseCtx(ctx, func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				return 0, ctx.Err()
+			})
+			cancel()
+			_, err := fut.Value()
+
+			Convey("Then the function should observe it", func() {
+				So(err, ShouldEqual, context.Canceled)
+			})
+		})
+
+		Convey("When the function panics", func() {
+			fut := PromiseCtx(context.Background(), func(ctx context.Context) (int, error) {
+				panic("ctx-panic")
+			})
+			_, err := fut.Value()
+
+			Convey("Then it should return the panic as an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "ctx-panic")
+			})
+		})
+	})
+
+	Convey("Given a slow future", t, func() {
+		release := make(chan struct{})
+		fut := Promise(func() int {
+			<-release
+			return 9
+		})
+
+		Convey("When ValueCtx gives up on a timeout", func() {
+			cause := errors.New("too slow")
+			ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Millisecond, cause)
+			defer cancel()
+			val, err := fut.ValueCtx(ctx)
+
+			Convey("Then it should return the cause of the context", func() {
+				So(val, ShouldEqual, 0)
+				So(err, ShouldEqual, cause)
+			})
+
+			Convey("And the value should still be available later", func() {
+				close(release)
+				val, err := fut.Value()
+				So(val, ShouldEqual, 9)
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestFutureMemoized(t *testing.T) {
+	Convey("Given a completed future", t, func() {
+		fut := PromiseErr(func() (int, error) {
+			return 3, errors.New("partial")
+		})
+
+		Convey("When Value is called repeatedly", func() {
+			v1, err1 := fut.Value()
+			v2, err2 := fut.Value()
+
+			Convey("Then every call should return the same result", func() {
+				So(v1, ShouldEqual, 3)
+				So(v2, ShouldEqual, 3)
+				So(err1, ShouldNotBeNil)
+				So(err2, ShouldEqual, err1)
+			})
+		})
+
+		Convey("When Value is called from many goroutines", func() {
+			var wg sync.WaitGroup
+			results := make(chan int, 10)
+			for i := 0; i < 10; i++ {
+				wg.Go(func() {
+					v, _ := fut.Value()
+					results <- v
+				})
+			}
+			wg.Wait()
+			close(results)
+
+			Convey("Then every goroutine should get the value", func() {
+				for v := range results {
+					So(v, ShouldEqual, 3)
+				}
+			})
+		})
+	})
+
+	Convey("Given a pending future", t, func() {
+		release := make(chan struct{})
+		fut := Promise(func() string {
+			<-release
+			return "ready"
+		})
+
+		Convey("Poll should report that it is not ready", func() {
+			_, _, ok := fut.Poll()
+			So(ok, ShouldBeFalse)
+
+			select {
+			case <-fut.Done():
+				So("future should not be done", ShouldBeEmpty)
+			default:
+			}
+
+			Convey("And report the value once it completes", func() {
+				close(release)
+				<-fut.Done()
+
+				v, err, ok := fut.Poll()
+				So(ok, ShouldBeTrue)
+				So(v, ShouldEqual, "ready")
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestFutureGoexit(t *testing.T) {
+	Convey("Given a Promise whose function calls runtime.Goexit", t, func() {
+		fut := Promise(func() int {
+			runtime.Goexit()
+			return 1
+		})
+
+		Convey("Then it should complete with ErrGoexit", func() {
+			v, err := fut.Value()
+			So(v, ShouldEqual, 0)
+			So(err, ShouldEqual, try.ErrGoexit)
+		})
+	})
+}
@@ -0,0 +1,24 @@
+package future
+
+import (
+	"sync"
+
+	"github.com/WhiCu/async/try"
+)
+
+// Lazy creates a future that executes function f only once it is first awaited
+// with Value, ValueCtx, Err or Done, or by a future derived from it.
+// f runs at most once, in its own goroutine, and all callers share its result.
+// A panic in f is converted to error.
+func Lazy[T any](f func() (T, error)) *Future[T] {
+	future := newFuture[T]()
+	var once sync.Once
+	future.start = func() {
+		once.Do(func() {
+			future.run(func() (T, error) {
+				return try.TryValueErr(f)
+			})
+		})
+	}
+	return future
+}
@@ -0,0 +1,73 @@
+package future
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/WhiCu/async/try"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLazy(t *testing.T) {
+	Convey("Given a Lazy future", t, func() {
+		var calls atomic.Int32
+		fut := Lazy(func() (int, error) {
+			calls.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			return 8, nil
+		})
+
+		Convey("It should not run before it is awaited", func() {
+			time.Sleep(10 * time.Millisecond)
+			_, _, ok := fut.Poll()
+			So(ok, ShouldBeFalse)
+			So(calls.Load(), ShouldEqual, 0)
+		})
+
+		Convey("It should run once for concurrent callers", func() {
+			var wg sync.WaitGroup
+			var sum atomic.Int32
+			for i := 0; i < 10; i++ {
+				wg.Go(func() {
+					v, _ := fut.Value()
+					sum.Add(int32(v))
+				})
+			}
+			wg.Wait()
+
+			So(sum.Load(), ShouldEqual, 80)
+			So(calls.Load(), ShouldEqual, 1)
+			_, _, ok := fut.Poll()
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("It should start when a derived future is awaited", func() {
+			mapped := Map(fut, func(v int) int { return v + 1 })
+			time.Sleep(10 * time.Millisecond)
+			So(calls.Load(), ShouldEqual, 0)
+
+			v, err := mapped.Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 9)
+			So(calls.Load(), ShouldEqual, 1)
+		})
+
+		Convey("It should start when combined futures are awaited", func() {
+			vs, err := All(fut, Lazy(func() (int, error) { return 1, nil })).Value()
+			So(err, ShouldBeNil)
+			So(vs, ShouldResemble, []int{8, 1})
+		})
+	})
+
+	Convey("Given a Lazy future that panics", t, func() {
+		fut := Lazy(func() (int, error) { panic("lazy-boom") })
+
+		Convey("It should return the panic as an error", func() {
+			err := fut.Err()
+			So(try.AsPanicError(err), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "lazy-boom")
+		})
+	})
+}
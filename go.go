@@ -1,11 +1,40 @@
+// Package async provides helpers for running functions in goroutines
+// whose panics are recovered instead of crashing the process.
 package async
 
 import (
+	"github.com/WhiCu/async/future"
 	"github.com/WhiCu/async/try"
 )
 
+// GoTry runs f in a new goroutine and reports its panic to the panic handler set by try.SetPanicHandler.
+//
+// Deprecated: The type parameter is unused; use Go instead.
 func GoTry[T any](f func()) {
+	Go(f)
+}
+
+// Go runs f in a new goroutine.
+// The returned channel receives the panic of f as an error, try.ErrGoexit
+// if f called runtime.Goexit, or nil, and is then closed.
+// The panic is also reported to the panic handler set by try.SetPanicHandler,
+// so the channel may be ignored.
+func Go(f func()) <-chan error {
+	c := make(chan error, 1)
 	go func() {
-		try.Try(f)
+		defer close(c)
+		try.Run(func() error {
+			f()
+			return nil
+		}, func(err error) {
+			c <- err
+		})
 	}()
+	return c
+}
+
+// GoValue runs f in a new goroutine and returns a future holding its result.
+// A panic in f is held by the future and reported to the panic handler set by try.SetPanicHandler.
+func GoValue[T any](f func() T) *future.Future[T] {
+	return future.Promise(f)
 }
@@ -0,0 +1,83 @@
+package async
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/WhiCu/async/try"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGo(t *testing.T) {
+	Convey("Given a panic handler", t, func() {
+		reported := make(chan *try.PanicError, 2)
+		try.SetPanicHandler(func(pe *try.PanicError) { reported <- pe })
+		defer try.SetPanicHandler(nil)
+
+		Convey("Go should deliver and report a panic", func() {
+			err := <-Go(func() { panic("boom") })
+
+			So(try.AsPanicError(err), ShouldBeTrue)
+			pe := <-reported
+			So(pe.Value, ShouldEqual, "boom")
+		})
+
+		Convey("A panic should be reported once", func() {
+			<-Go(func() { panic("once") })
+
+			So((<-reported).Value, ShouldEqual, "once")
+			select {
+			case <-reported:
+				So("the panic should not be reported twice", ShouldBeEmpty)
+			default:
+			}
+		})
+
+		Convey("Go should deliver nil when f returns", func() {
+			err, ok := <-Go(func() {})
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			select {
+			case <-reported:
+				So("nothing should be reported", ShouldBeEmpty)
+			default:
+			}
+		})
+
+		Convey("GoTry should report a panic", func() {
+			GoTry[any](func() { panic("lost") })
+
+			select {
+			case pe := <-reported:
+				So(pe.Value, ShouldEqual, "lost")
+			case <-time.After(time.Second):
+				So("panic should be reported", ShouldBeEmpty)
+			}
+		})
+
+		Convey("GoValue should hold and report a panic", func() {
+			_, err := GoValue(func() int { panic("value") }).Value()
+
+			So(try.AsPanicError(err), ShouldBeTrue)
+			So((<-reported).Value, ShouldEqual, "value")
+		})
+
+		Convey("GoValue should return the value", func() {
+			v, err := GoValue(func() int { return 4 }).Value()
+			So(v, ShouldEqual, 4)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestGoGoexit(t *testing.T) {
+	Convey("Given a goroutine calling runtime.Goexit", t, func() {
+		err := <-Go(func() { runtime.Goexit() })
+
+		Convey("Go should deliver ErrGoexit", func() {
+			So(err, ShouldEqual, try.ErrGoexit)
+		})
+	})
+}
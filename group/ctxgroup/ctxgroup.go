@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/WhiCu/async/group"
+	"github.com/WhiCu/async/semaphore"
 	"github.com/WhiCu/async/try"
 	"github.com/WhiCu/async/utils/mergectx"
 )
@@ -15,10 +16,16 @@ type Group struct {
 
 	wg sync.WaitGroup
 
-	sem chan struct{}
+	sem semaphore.Semaphore
 
 	errOnce sync.Once
 	err     error
+
+	// abortOnce and abortErr hold the first error of a CtxGo that gave up waiting for a slot.
+	abortOnce sync.Once
+	abortErr  error
+
+	tryOpts try.OptionList
 }
 
 func WithContext(ctx context.Context) (*Group, context.Context) {
@@ -30,110 +37,158 @@ func (g *Group) increment() {
 	g.wg.Add(1)
 }
 
-func (g *Group) decrement() {
-	if g.sem != nil {
-		<-g.sem
-	}
+func (g *Group) decrement(w int64) {
+	g.sem.Release(w)
 	g.wg.Done()
 }
 
-func (g *Group) tryAcquire() bool {
-	if g.sem == nil {
-		return true
+func (g *Group) tryAcquire(w int64) bool {
+	return g.sem.TryAcquire(w)
+}
+
+// acquire waits for a weight of w until ctx or the group is done,
+// in which case it returns the cause of the cancellation.
+func (g *Group) acquire(ctx context.Context, w int64) error {
+	if g.tryAcquire(w) {
+		return nil
 	}
-	select {
-	case g.sem <- struct{}{}:
-		return true
-	default:
-		return false
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if g.Ctx != nil {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		stop := context.AfterFunc(g.Ctx, func() {
+			cancel(context.Cause(g.Ctx))
+		})
+		defer stop()
 	}
+	return g.sem.Acquire(ctx, w)
 }
 
 func mergeCtx(primary, secondary context.Context) context.Context {
 	return mergectx.MergeContext(primary, secondary)
 }
 
+// context returns the context passed to a task started with ctx.
+func (g *Group) context(ctx context.Context) context.Context {
+	switch {
+	case ctx == nil || ctx.Done() == nil:
+		return g.Ctx
+	default:
+		return mergeCtx(g.Ctx, ctx)
+	}
+}
+
+// fail records err as the error of the group, if it is the first one, and cancels the group.
+func (g *Group) fail(err error) {
+	g.errOnce.Do(func() {
+		g.err = err
+		g.Cancel()
+	})
+}
+
+// abort records err as the error of a goroutine that was never started.
+// Unlike fail, it leaves the running goroutines alone.
+func (g *Group) abort(err error) {
+	g.abortOnce.Do(func() {
+		g.abortErr = err
+	})
+}
+
+func (g *Group) done(w int64, err error) {
+	if err != nil {
+		g.fail(err)
+	}
+	g.decrement(w)
+}
+
 func (g *Group) rawGo(f func(context.Context), ctx context.Context) {
-	g.increment()
-	go func() {
-		if err := try.Try(func() { f(ctx) }); err != nil {
-			g.errOnce.Do(func() {
-				g.err = err
-				g.Cancel()
-			})
-		}
-		g.decrement()
-	}()
+	g.rawGoErr(1, func(ctx context.Context) error {
+		f(ctx)
+		return nil
+	}, ctx)
 }
 
-func (g *Group) rawGoErr(f func(context.Context) error, ctx context.Context) {
+func (g *Group) rawGoErr(w int64, f func(context.Context) error, ctx context.Context) {
 	g.increment()
+	opts := g.tryOpts.Load()
 	go func() {
-		if err := try.TryErr(func() error { return f(ctx) }); err != nil {
-			g.errOnce.Do(func() {
-				g.err = err
-				g.Cancel()
-			})
-		}
-		g.decrement()
+		try.Run(func() error { return f(ctx) }, func(err error) { g.done(w, err) }, opts...)
 	}()
 
 }
 
+// CtxGo runs f in a new goroutine once the group is below its limit.
+// If ctx or the group is done before a slot frees up, f is not run and
+// Wait returns the cause of the cancellation unless a goroutine failed;
+// the other goroutines of the group keep running.
 func (g *Group) CtxGo(ctx context.Context, f func(context.Context)) {
-	g.tryAcquire()
-
-	switch {
-	case ctx == nil || ctx.Done() == nil:
-		ctx = g.Ctx
-	default:
-		ctx = mergeCtx(g.Ctx, ctx)
+	if err := g.acquire(ctx, 1); err != nil {
+		g.abort(err)
+		return
 	}
-	g.rawGo(f, ctx)
+	g.rawGo(f, g.context(ctx))
 
 }
 
+// CtxGoErr is like CtxGo for a function that returns an error.
 func (g *Group) CtxGoErr(ctx context.Context, f func(context.Context) error) {
-	g.tryAcquire()
-
-	switch {
-	case ctx == nil || ctx.Done() == nil:
-		ctx = g.Ctx
-	default:
-		ctx = mergeCtx(g.Ctx, ctx)
+	if err := g.acquire(ctx, 1); err != nil {
+		g.abort(err)
+		return
 	}
-	g.rawGoErr(f, ctx)
+	g.rawGoErr(1, f, g.context(ctx))
 
 }
 
-func (g *Group) CtxTryGo(ctx context.Context, f func(context.Context)) error {
-	if !g.tryAcquire() {
+// CtxGoWeighted is like CtxGoErr for a goroutine that counts as w against the limit of the group.
+// It waits until the goroutines started before it have been admitted and w fits.
+// If w is above the limit, it returns group.ErrLimitExceeded without waiting.
+// Lowering the limit below w while CtxGoWeighted waits holds it, and the goroutines
+// started after it, until the limit is raised again or ctx or the group is done.
+// Unlike CtxGo, it returns the cause of such a cancellation instead of failing the group.
+func (g *Group) CtxGoWeighted(ctx context.Context, w int64, f func(context.Context) error) error {
+	if l := g.sem.Limit(); l >= 0 && w > l {
 		return group.ErrLimitExceeded
 	}
+	if err := g.acquire(ctx, w); err != nil {
+		return err
+	}
+	g.rawGoErr(w, f, g.context(ctx))
+	return nil
+}
 
-	switch {
-	case ctx == nil || ctx.Done() == nil:
-		ctx = g.Ctx
-	default:
-		ctx = mergeCtx(g.Ctx, ctx)
+func (g *Group) CtxTryGo(ctx context.Context, f func(context.Context)) error {
+	if !g.tryAcquire(1) {
+		return group.ErrLimitExceeded
 	}
-	g.rawGo(f, ctx)
+
+	g.rawGo(f, g.context(ctx))
 
 	return nil
 }
 
 func (g *Group) CtxTryGoErr(ctx context.Context, f func(context.Context) error) error {
-	if !g.tryAcquire() {
+	if !g.tryAcquire(1) {
 		return group.ErrLimitExceeded
 	}
 
-	switch {
-	case ctx == nil || ctx.Done() == nil:
-		ctx = g.Ctx
-	default:
-		ctx = mergeCtx(g.Ctx, ctx)
+	g.rawGoErr(1, f, g.context(ctx))
+
+	return nil
+}
+
+// CtxTryGoWeighted is like CtxGoWeighted but returns group.ErrLimitExceeded
+// instead of waiting if w does not fit right away.
+func (g *Group) CtxTryGoWeighted(ctx context.Context, w int64, f func(context.Context) error) error {
+	if !g.tryAcquire(w) {
+		return group.ErrLimitExceeded
 	}
-	g.rawGoErr(f, ctx)
+
+	g.rawGoErr(w, f, g.context(ctx))
 
 	return nil
 }
@@ -152,19 +207,37 @@ func (g *Group) Cancel() {
 func (g *Group) Wait() error {
 	g.wg.Wait()
 	g.Cancel()
-	return g.err
+	if g.err != nil {
+		return g.err
+	}
+	return g.abortErr
 }
 
+// SetLimit limits the total weight of the goroutines in the group to n; a negative n removes the limit.
+// It can be called while goroutines are running: raising the limit starts
+// the goroutines waiting in CtxGo, lowering it takes effect as running goroutines finish.
 func (g *Group) SetLimit(n int) error {
-	if len(g.sem) != 0 {
-		return group.ErrModifyLimit
-	}
-
 	if n < 0 {
-		g.sem = nil
+		g.sem.Resize(-1)
 		return group.ErrNegativeLimit
 	}
-
-	g.sem = make(chan struct{}, n)
+	g.sem.Resize(int64(n))
 	return nil
 }
+
+// Limit returns the current limit, or -1 if there is none.
+func (g *Group) Limit() int {
+	return int(g.sem.Limit())
+}
+
+// InFlight returns the total weight of the goroutines running in the group.
+// Goroutines not started by CtxGoWeighted or CtxTryGoWeighted weigh 1.
+func (g *Group) InFlight() int {
+	return int(g.sem.Acquired())
+}
+
+// SetTryOptions sets the options used to recover the panics of the goroutines
+// started afterwards, for example try.WithFilter to let programming errors crash the process.
+func (g *Group) SetTryOptions(opts ...try.Option) {
+	g.tryOpts.Store(opts...)
+}
@@ -3,12 +3,15 @@ package ctxgroup_test
 import (
 	"context"
 	"errors"
+	"runtime"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/WhiCu/async/group"
 	"github.com/WhiCu/async/group/ctxgroup"
+	"github.com/WhiCu/async/internal/testutil"
+	"github.com/WhiCu/async/try"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -38,6 +41,33 @@ func TestGroup_Behavior(t *testing.T) {
 			So(context.Cause(groupCtx).Error(), ShouldContainSubstring, "boom")
 		})
 
+		Convey("It should recover panics accepted by SetTryOptions()", func() {
+			g.SetTryOptions(try.WithFilter(try.IsUserValue))
+			g.CtxGo(context.Background(), func(ctx context.Context) { panic("boom") })
+
+			So(try.AsPanicError(g.Wait()), ShouldBeTrue)
+		})
+
+		Convey("It should keep the options a goroutine was started with", func() {
+			release := make(chan struct{})
+			g.CtxGo(context.Background(), func(ctx context.Context) {
+				<-release
+				var m map[string]int
+				m["boom"] = 1
+			})
+			g.SetTryOptions(try.WithFilter(try.IsUserValue))
+			close(release)
+
+			So(try.AsPanicError(g.Wait()), ShouldBeTrue)
+		})
+
+		Convey("It should report runtime.Goexit and cancel the group", func() {
+			g.CtxGo(context.Background(), func(ctx context.Context) { runtime.Goexit() })
+
+			So(g.Wait(), ShouldEqual, try.ErrGoexit)
+			So(context.Cause(groupCtx), ShouldEqual, try.ErrGoexit)
+		})
+
 		Convey("It should propagate error from CtxGoErr()", func() {
 			testErr := errors.New("something went wrong")
 
@@ -70,10 +100,14 @@ func TestGroup_Behavior(t *testing.T) {
 			So(called.Load(), ShouldEqual, 2)
 		})
 
-		Convey("It should return ErrModifyLimit if SetLimit called again after work started", func() {
+		Convey("It should start waiting goroutines when the limit is raised", func() {
 			So(g.SetLimit(1), ShouldBeNil)
 			g.CtxGo(context.Background(), func(ctx context.Context) { <-ctx.Done() })
-			So(g.SetLimit(2), ShouldEqual, group.ErrModifyLimit)
+			time.AfterFunc(10*time.Millisecond, func() { _ = g.SetLimit(2) })
+
+			g.CtxGo(context.Background(), func(ctx context.Context) { <-ctx.Done() })
+			So(g.Limit(), ShouldEqual, 2)
+			So(g.InFlight(), ShouldEqual, 2)
 
 			g.Cancel()
 			So(g.Wait(), ShouldBeNil)
@@ -146,3 +180,150 @@ func TestGroup_IsolatedCancellation(t *testing.T) {
 		So(group2.Wait(), ShouldBeNil)
 	})
 }
+
+func TestGroup_Limit(t *testing.T) {
+	Convey("Given a group with a limit", t, func() {
+		g, groupCtx := ctxgroup.WithContext(context.Background())
+		So(g.SetLimit(3), ShouldBeNil)
+
+		Convey("CtxGo should never run more goroutines than the limit", func() {
+			var running testutil.Gauge
+			var count atomic.Int32
+			for i := 0; i < 20; i++ {
+				g.CtxGo(context.Background(), func(ctx context.Context) {
+					running.Hold(1, 5*time.Millisecond)
+					count.Add(1)
+				})
+			}
+
+			So(g.Wait(), ShouldBeNil)
+			So(count.Load(), ShouldEqual, 20)
+			So(running.Peak(), ShouldEqual, 3)
+		})
+
+		Convey("CtxGoErr should stop waiting when its context is canceled", func() {
+			release := make(chan struct{})
+			for i := 0; i < 3; i++ {
+				g.CtxGo(context.Background(), func(ctx context.Context) { <-release })
+			}
+
+			cause := errors.New("give up")
+			ctx, cancel := context.WithCancelCause(context.Background())
+			time.AfterFunc(10*time.Millisecond, func() { cancel(cause) })
+
+			var called atomic.Bool
+			g.CtxGoErr(ctx, func(ctx context.Context) error {
+				called.Store(true)
+				return nil
+			})
+
+			So(groupCtx.Err(), ShouldBeNil)
+			close(release)
+			So(g.Wait(), ShouldEqual, cause)
+			So(called.Load(), ShouldBeFalse)
+		})
+
+		Convey("A task error should still cancel the group after CtxGo gave up", func() {
+			release := make(chan struct{})
+			for i := 0; i < 3; i++ {
+				g.CtxGoErr(context.Background(), func(ctx context.Context) error {
+					<-release
+					return nil
+				})
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			g.CtxGo(ctx, func(ctx context.Context) {})
+
+			testErr := errors.New("fail")
+			close(release)
+			g.CtxGoErr(context.Background(), func(ctx context.Context) error { return testErr })
+
+			So(g.Wait(), ShouldEqual, testErr)
+			So(context.Cause(groupCtx), ShouldEqual, testErr)
+		})
+
+		Convey("CtxGo should stop waiting when the group is canceled", func() {
+			release := make(chan struct{})
+			for i := 0; i < 3; i++ {
+				g.CtxGo(context.Background(), func(ctx context.Context) { <-release })
+			}
+			time.AfterFunc(10*time.Millisecond, g.Cancel)
+
+			var called atomic.Bool
+			g.CtxGo(context.Background(), func(ctx context.Context) { called.Store(true) })
+
+			close(release)
+			So(g.Wait(), ShouldEqual, context.Canceled)
+			So(called.Load(), ShouldBeFalse)
+		})
+	})
+}
+
+func TestGroup_Weighted(t *testing.T) {
+	Convey("Given a group with a weight limit", t, func() {
+		g, _ := ctxgroup.WithContext(context.Background())
+		So(g.SetLimit(10), ShouldBeNil)
+
+		Convey("CtxTryGoWeighted should fail if the weight does not fit", func() {
+			release := make(chan struct{})
+			err := g.CtxTryGoWeighted(context.Background(), 8, func(ctx context.Context) error {
+				<-release
+				return nil
+			})
+			So(err, ShouldBeNil)
+			So(g.InFlight(), ShouldEqual, 8)
+
+			err = g.CtxTryGoWeighted(context.Background(), 3, func(ctx context.Context) error { return nil })
+			So(err, ShouldEqual, group.ErrLimitExceeded)
+
+			close(release)
+			So(g.Wait(), ShouldBeNil)
+		})
+
+		Convey("CtxGoWeighted should wait until the weight fits", func() {
+			release := make(chan struct{})
+			err := g.CtxGoWeighted(context.Background(), 8, func(ctx context.Context) error {
+				<-release
+				return nil
+			})
+			So(err, ShouldBeNil)
+			time.AfterFunc(10*time.Millisecond, func() { close(release) })
+
+			var started atomic.Int64
+			err = g.CtxGoWeighted(context.Background(), 10, func(ctx context.Context) error {
+				started.Store(int64(g.InFlight()))
+				return nil
+			})
+			So(err, ShouldBeNil)
+
+			So(g.Wait(), ShouldBeNil)
+			So(started.Load(), ShouldEqual, 10)
+		})
+
+		Convey("CtxGoWeighted should not wait for a weight above the limit", func() {
+			err := g.CtxGoWeighted(context.Background(), 11, func(ctx context.Context) error { return nil })
+			So(err, ShouldEqual, group.ErrLimitExceeded)
+			So(g.Wait(), ShouldBeNil)
+		})
+
+		Convey("CtxGoWeighted should return the cause when its context is canceled", func() {
+			release := make(chan struct{})
+			err := g.CtxGoWeighted(context.Background(), 10, func(ctx context.Context) error {
+				<-release
+				return nil
+			})
+			So(err, ShouldBeNil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			err = g.CtxGoWeighted(ctx, 1, func(ctx context.Context) error { return nil })
+			So(err, ShouldEqual, context.DeadlineExceeded)
+
+			close(release)
+			So(g.Wait(), ShouldBeNil)
+		})
+	})
+}
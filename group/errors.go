@@ -2,10 +2,61 @@ package group
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 var (
 	ErrLimitExceeded = errors.New("group: limit exceeded")
+	// ErrModifyLimit was returned by SetLimit while goroutines in the group were running.
+	//
+	// Deprecated: The limit of a group can be changed while goroutines are running,
+	// so ErrModifyLimit is no longer returned.
 	ErrModifyLimit   = errors.New("group: modify limit while goroutines in the group are still active")
 	ErrNegativeLimit = errors.New("group: negative limit")
 )
+
+// TaskError is the error of a task, numbered from zero in the order the tasks were started.
+type TaskError struct {
+	Index int
+	Err   error
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("group: task %d: %v", e.Index, e.Err)
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError holds the errors of all failed tasks of a group, ordered by task index.
+// It unwraps to them, so errors.Is, errors.As and try.AsPanicError look through it
+// the same way they look through the result of errors.Join.
+type MultiError struct {
+	Errors []*TaskError
+	// Omitted is the number of errors that were not kept because of the limit.
+	Omitted int
+}
+
+func (e *MultiError) Error() string {
+	var b strings.Builder
+	for i, err := range e.Errors {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(err.Error())
+	}
+	if e.Omitted > 0 {
+		fmt.Fprintf(&b, "\ngroup: %d more errors omitted", e.Omitted)
+	}
+	return b.String()
+}
+
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
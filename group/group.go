@@ -12,8 +12,14 @@ type ErrRunner interface {
 	TryGoErr(func() error) error
 }
 
+// Limiter limits the number of goroutines running in a group, or their total
+// weight if some of them are weighted. The limit can be changed while goroutines are running.
 type Limiter interface {
 	SetLimit(limit int) error
+	// Limit returns the current limit, or -1 if there is none.
+	Limit() int
+	// InFlight returns the number, or total weight, of the goroutines running in the group.
+	InFlight() int
 }
 
 type Waiter interface {
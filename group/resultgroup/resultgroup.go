@@ -0,0 +1,104 @@
+// Package resultgroup provides a group of goroutines that each produce a value,
+// collected in the order the goroutines were started.
+package resultgroup
+
+import (
+	"sync"
+
+	"github.com/WhiCu/async/group/safegroup"
+)
+
+// Group runs functions returning a value and an error in goroutines and collects
+// their values. It follows the semantics of safegroup.Group: Wait returns the first
+// error, a panic becomes a *try.PanicError, and the limit set by SetLimit can be
+// changed while goroutines are running.
+// The zero value is ready to use.
+type Group[T any] struct {
+	g safegroup.Group
+
+	mu      sync.Mutex
+	results []result[T]
+}
+
+type result[T any] struct {
+	value T
+	// skipped marks the slot of a goroutine TryGo could not start.
+	skipped bool
+}
+
+// reserve appends a slot for the result of the next goroutine and returns its index.
+func (g *Group[T]) reserve() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.results = append(g.results, result[T]{})
+	return len(g.results) - 1
+}
+
+// skip marks the slot at index i as not holding a result.
+func (g *Group[T]) skip(i int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.results[i].skipped = true
+}
+
+// task returns the function storing the value of f at index i.
+func (g *Group[T]) task(i int, f func() (T, error)) func() error {
+	return func() error {
+		v, err := f()
+		g.mu.Lock()
+		g.results[i].value = v
+		g.mu.Unlock()
+		return err
+	}
+}
+
+// Go runs f in a new goroutine, waiting until the group is below its limit.
+func (g *Group[T]) Go(f func() (T, error)) {
+	g.g.GoErr(g.task(g.reserve(), f))
+}
+
+// TryGo is like Go but returns group.ErrLimitExceeded instead of waiting
+// if the group is at its limit.
+func (g *Group[T]) TryGo(f func() (T, error)) error {
+	i := g.reserve()
+	if err := g.g.TryGoErr(g.task(i, f)); err != nil {
+		g.skip(i)
+		return err
+	}
+	return nil
+}
+
+// Wait waits for all goroutines started so far and returns their values
+// in the order they were started, along with the first error.
+// The value of a goroutine that panicked is the zero value of T.
+func (g *Group[T]) Wait() ([]T, error) {
+	err := g.g.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	values := make([]T, 0, len(g.results))
+	for _, r := range g.results {
+		if !r.skipped {
+			values = append(values, r.value)
+		}
+	}
+	return values, err
+}
+
+// SetLimit limits the number of goroutines in the group to n; a negative n removes the limit.
+func (g *Group[T]) SetLimit(n int) error {
+	return g.g.SetLimit(n)
+}
+
+// Limit returns the current limit, or -1 if there is none.
+func (g *Group[T]) Limit() int {
+	return g.g.Limit()
+}
+
+// InFlight returns the number of goroutines running in the group.
+func (g *Group[T]) InFlight() int {
+	return g.g.InFlight()
+}
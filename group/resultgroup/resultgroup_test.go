@@ -0,0 +1,103 @@
+package resultgroup_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/WhiCu/async/group"
+	"github.com/WhiCu/async/group/resultgroup"
+	"github.com/WhiCu/async/try"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var _ group.Limiter = (*resultgroup.Group[int])(nil)
+
+func TestResultGroup(t *testing.T) {
+	Convey("Given a result group", t, func() {
+		var g resultgroup.Group[int]
+
+		Convey("It should return the values in submission order", func() {
+			for i := 0; i < 10; i++ {
+				g.Go(func() (int, error) {
+					time.Sleep(time.Duration(10-i) * time.Millisecond)
+					return i * i, nil
+				})
+			}
+
+			values, err := g.Wait()
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, []int{0, 1, 4, 9, 16, 25, 36, 49, 64, 81})
+		})
+
+		Convey("It should return the first error", func() {
+			testErr := errors.New("fail")
+			g.Go(func() (int, error) { return 1, nil })
+			g.Go(func() (int, error) { return 0, testErr })
+
+			values, err := g.Wait()
+			So(err, ShouldEqual, testErr)
+			So(values, ShouldResemble, []int{1, 0})
+		})
+
+		Convey("It should turn a panic into a PanicError", func() {
+			g.Go(func() (int, error) { panic("boom") })
+			g.Go(func() (int, error) { return 2, nil })
+
+			values, err := g.Wait()
+			So(try.AsPanicError(err), ShouldBeTrue)
+			So(values, ShouldResemble, []int{0, 2})
+		})
+
+		Convey("It should respect SetLimit()", func() {
+			So(g.SetLimit(1), ShouldBeNil)
+			So(g.Limit(), ShouldEqual, 1)
+
+			release := make(chan struct{})
+			So(g.TryGo(func() (int, error) {
+				<-release
+				return 1, nil
+			}), ShouldBeNil)
+			So(g.InFlight(), ShouldEqual, 1)
+			So(g.TryGo(func() (int, error) { return 2, nil }), ShouldEqual, group.ErrLimitExceeded)
+
+			close(release)
+			g.Go(func() (int, error) { return 3, nil })
+
+			values, err := g.Wait()
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, []int{1, 3})
+		})
+
+		Convey("TryGo should not wait behind a waiting Go", func() {
+			So(g.SetLimit(1), ShouldBeNil)
+
+			release := make(chan struct{})
+			g.Go(func() (int, error) {
+				<-release
+				return 1, nil
+			})
+			started := make(chan struct{})
+			go func() {
+				g.Go(func() (int, error) { return 2, nil })
+				close(started)
+			}()
+			time.Sleep(10 * time.Millisecond)
+
+			tried := make(chan error, 1)
+			go func() { tried <- g.TryGo(func() (int, error) { return 3, nil }) }()
+			select {
+			case err := <-tried:
+				So(err, ShouldEqual, group.ErrLimitExceeded)
+			case <-time.After(time.Second):
+				So("TryGo should return", ShouldBeEmpty)
+			}
+
+			close(release)
+			<-started
+			values, err := g.Wait()
+			So(err, ShouldBeNil)
+			So(values, ShouldResemble, []int{1, 2})
+		})
+	})
+}
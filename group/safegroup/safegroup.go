@@ -1,79 +1,115 @@
 package safegroup
 
 import (
+	"context"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/WhiCu/async/group"
+	"github.com/WhiCu/async/semaphore"
 	"github.com/WhiCu/async/try"
 )
 
 type Group struct {
 	wg sync.WaitGroup
 
-	sem chan struct{}
+	sem semaphore.Semaphore
 
 	errOnce sync.Once
 	err     error
+
+	// started numbers the tasks for the errors collected by CollectErrors.
+	started  atomic.Int64
+	collect  atomic.Bool
+	mu       sync.Mutex
+	errLimit int
+	errs     []*group.TaskError
+	omitted  int
+
+	tryOpts try.OptionList
 }
 
-func (g *Group) increment() {
-	if g.sem != nil {
-		g.sem <- struct{}{}
+func (g *Group) increment(w int64) {
+	_ = g.sem.Acquire(context.Background(), w)
+}
+
+func (g *Group) decrement(w int64) {
+	g.sem.Release(w)
+}
+
+func (g *Group) done(w int64, index int, err error) {
+	switch {
+	case err == nil:
+	case g.collect.Load():
+		g.collectErr(index, err)
+	default:
+		g.errOnce.Do(func() {
+			g.err = err
+		})
 	}
+	g.decrement(w)
 }
 
-func (g *Group) decrement() {
-	if g.sem != nil {
-		<-g.sem
+func (g *Group) collectErr(index int, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.errLimit > 0 && len(g.errs) >= g.errLimit {
+		g.omitted++
+		return
 	}
+	g.errs = append(g.errs, &group.TaskError{Index: index, Err: err})
 }
 
 func (g *Group) rawGo(f func()) {
-	g.wg.Go(
-		func() {
-			if err := try.Try(f); err != nil {
-				g.errOnce.Do(func() {
-					g.err = err
-				})
-			}
-			g.decrement()
-		},
-	)
+	g.rawGoErr(1, func() error {
+		f()
+		return nil
+	})
 }
 
-func (g *Group) rawGoErr(f func() error) {
+func (g *Group) rawGoErr(w int64, f func() error) {
+	index := int(g.started.Add(1) - 1)
+	opts := g.tryOpts.Load()
 	g.wg.Go(
 		func() {
-			if err := try.TryErr(f); err != nil {
-				g.errOnce.Do(func() {
-					g.err = err
-				})
-			}
-			g.decrement()
+			try.Run(f, func(err error) { g.done(w, index, err) }, opts...)
 		},
 	)
 }
 
 func (g *Group) Go(f func()) {
-	g.increment()
+	g.increment(1)
 
 	g.rawGo(f)
 
 }
 
 func (g *Group) GoErr(f func() error) {
-	g.increment()
+	g.increment(1)
+
+	g.rawGoErr(1, f)
+}
 
-	g.rawGoErr(f)
+// GoWeighted runs f in a new goroutine that counts as w against the limit of the group,
+// waiting until the goroutines started before it have been admitted and w fits.
+// If w is above the limit, it returns group.ErrLimitExceeded without waiting.
+// Lowering the limit below w while GoWeighted waits holds it, and the goroutines
+// started after it, until the limit is raised again.
+func (g *Group) GoWeighted(w int64, f func() error) error {
+	if l := g.sem.Limit(); l >= 0 && w > l {
+		return group.ErrLimitExceeded
+	}
+	g.increment(w)
+
+	g.rawGoErr(w, f)
+	return nil
 }
 
 func (g *Group) TryGo(f func()) error {
-	if g.sem != nil {
-		select {
-		case g.sem <- struct{}{}:
-		default:
-			return group.ErrLimitExceeded
-		}
+	if !g.sem.TryAcquire(1) {
+		return group.ErrLimitExceeded
 	}
 
 	g.rawGo(f)
@@ -81,32 +117,83 @@ func (g *Group) TryGo(f func()) error {
 }
 
 func (g *Group) TryGoErr(f func() error) error {
-	if g.sem != nil {
-		select {
-		case g.sem <- struct{}{}:
-		default:
-			return group.ErrLimitExceeded
-		}
+	if !g.sem.TryAcquire(1) {
+		return group.ErrLimitExceeded
+	}
+
+	g.rawGoErr(1, f)
+	return nil
+}
+
+// TryGoWeighted is like GoWeighted but returns group.ErrLimitExceeded
+// instead of waiting if w does not fit right away.
+func (g *Group) TryGoWeighted(w int64, f func() error) error {
+	if !g.sem.TryAcquire(w) {
+		return group.ErrLimitExceeded
 	}
 
-	g.rawGoErr(f)
+	g.rawGoErr(w, f)
 	return nil
 }
 
 func (g *Group) Wait() error {
 	g.wg.Wait()
+	if g.collect.Load() {
+		return g.collected()
+	}
 	return g.err
 }
 
-func (g *Group) SetLimit(n int) error {
-	if len(g.sem) != 0 {
-		return group.ErrModifyLimit
+// collected returns the collected errors as a *group.MultiError, or nil if there are none.
+func (g *Group) collected() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if len(g.errs) == 0 {
+		return nil
 	}
+	errs := slices.Clone(g.errs)
+	slices.SortFunc(errs, func(a, b *group.TaskError) int {
+		return a.Index - b.Index
+	})
+	return &group.MultiError{Errors: errs, Omitted: g.omitted}
+}
 
+// CollectErrors makes Wait return the errors of all failed tasks as a *group.MultiError
+// instead of only the first error. At most limit errors are kept, the ones that occur first;
+// a limit less than 1 keeps all of them. It must be called before any task is started.
+func (g *Group) CollectErrors(limit int) {
+	g.mu.Lock()
+	g.errLimit = limit
+	g.mu.Unlock()
+	g.collect.Store(true)
+}
+
+// SetLimit limits the total weight of the goroutines in the group to n; a negative n removes the limit.
+// It can be called while goroutines are running: raising the limit starts
+// the goroutines waiting in Go, lowering it takes effect as running goroutines finish.
+func (g *Group) SetLimit(n int) error {
 	if n < 0 {
-		g.sem = nil
+		g.sem.Resize(-1)
 		return group.ErrNegativeLimit
 	}
-	g.sem = make(chan struct{}, n)
+	g.sem.Resize(int64(n))
 	return nil
 }
+
+// Limit returns the current limit, or -1 if there is none.
+func (g *Group) Limit() int {
+	return int(g.sem.Limit())
+}
+
+// InFlight returns the total weight of the goroutines running in the group.
+// Goroutines not started by GoWeighted or TryGoWeighted weigh 1.
+func (g *Group) InFlight() int {
+	return int(g.sem.Acquired())
+}
+
+// SetTryOptions sets the options used to recover the panics of the goroutines
+// started afterwards, for example try.WithFilter to let programming errors crash the process.
+func (g *Group) SetTryOptions(opts ...try.Option) {
+	g.tryOpts.Store(opts...)
+}
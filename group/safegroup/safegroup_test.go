@@ -2,12 +2,14 @@ package safegroup_test
 
 import (
 	"errors"
+	"runtime"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/WhiCu/async/group"
 	"github.com/WhiCu/async/group/safegroup"
+	"github.com/WhiCu/async/internal/testutil"
 	"github.com/WhiCu/async/try"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -37,6 +39,32 @@ func TestSafeGroup(t *testing.T) {
 			So(try.AsPanicError(err), ShouldBeTrue)
 		})
 
+		Convey("It should report runtime.Goexit from a goroutine", func() {
+			sg.Go(func() { runtime.Goexit() })
+
+			So(sg.Wait(), ShouldEqual, try.ErrGoexit)
+		})
+
+		Convey("It should recover panics accepted by SetTryOptions()", func() {
+			sg.SetTryOptions(try.WithFilter(try.IsNonError))
+			sg.Go(func() { panic("boom") })
+
+			So(try.AsPanicError(sg.Wait()), ShouldBeTrue)
+		})
+
+		Convey("It should keep the options a goroutine was started with", func() {
+			release := make(chan struct{})
+			sg.Go(func() {
+				<-release
+				var m map[string]int
+				m["boom"] = 1
+			})
+			sg.SetTryOptions(try.WithFilter(try.IsUserValue))
+			close(release)
+
+			So(try.AsPanicError(sg.Wait()), ShouldBeTrue)
+		})
+
 		Convey("It should respect SetLimit()", func() {
 			So(sg.SetLimit(1), ShouldBeNil)
 
@@ -84,19 +112,132 @@ func TestSafeGroup(t *testing.T) {
 			So(waitErr, ShouldEqual, testErr)
 		})
 
-		Convey("It should return ErrModifyLimit if SetLimit is called after work started", func() {
+		Convey("It should change the limit while goroutines are running", func() {
 			So(sg.SetLimit(1), ShouldBeNil)
-			sg.Go(func() { time.Sleep(50 * time.Millisecond) })
 
-			err := sg.SetLimit(2)
-			So(err, ShouldEqual, group.ErrModifyLimit)
+			release := make(chan struct{})
+			for i := 0; i < 3; i++ {
+				sg.Go(func() { <-release })
+				if i == 0 {
+					So(sg.InFlight(), ShouldEqual, 1)
+					So(sg.SetLimit(3), ShouldBeNil)
+				}
+			}
+			So(sg.Limit(), ShouldEqual, 3)
+			So(sg.InFlight(), ShouldEqual, 3)
 
+			So(sg.SetLimit(1), ShouldBeNil)
+			close(release)
+			So(sg.Wait(), ShouldBeNil)
+			So(sg.InFlight(), ShouldEqual, 0)
+			So(sg.TryGo(func() {}), ShouldBeNil)
 			So(sg.Wait(), ShouldBeNil)
 		})
 
 		Convey("It should return ErrNegativeLimit for negative limit", func() {
 			err := sg.SetLimit(-1)
 			So(err, ShouldEqual, group.ErrNegativeLimit)
+			So(sg.Limit(), ShouldEqual, -1)
+		})
+	})
+}
+
+func TestSafeGroup_CollectErrors(t *testing.T) {
+	Convey("Given a SafeGroup collecting errors", t, func() {
+		var sg safegroup.Group
+		testErr := errors.New("fail")
+
+		run := func() {
+			for i := 0; i < 100; i++ {
+				sg.GoErr(func() error {
+					switch {
+					case i == 50:
+						panic("boom")
+					case i%5 == 0:
+						return testErr
+					}
+					return nil
+				})
+			}
+		}
+
+		Convey("It should return every error with its task index", func() {
+			sg.CollectErrors(0)
+			run()
+
+			err := sg.Wait()
+			var me *group.MultiError
+			So(errors.As(err, &me), ShouldBeTrue)
+			So(me.Errors, ShouldHaveLength, 20)
+			So(me.Omitted, ShouldEqual, 0)
+			for i, te := range me.Errors {
+				So(te.Index, ShouldEqual, i*5)
+			}
+
+			So(errors.Is(err, testErr), ShouldBeTrue)
+			So(try.AsPanicError(err), ShouldBeTrue)
+			So(try.AsPanicError(me.Errors[10]), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "group: task 50: panic: boom")
+		})
+
+		Convey("It should keep at most limit errors", func() {
+			sg.CollectErrors(5)
+			run()
+
+			var me *group.MultiError
+			So(errors.As(sg.Wait(), &me), ShouldBeTrue)
+			So(me.Errors, ShouldHaveLength, 5)
+			So(me.Omitted, ShouldEqual, 15)
+		})
+
+		Convey("It should return nil if no task failed", func() {
+			sg.CollectErrors(0)
+			sg.Go(func() {})
+
+			So(sg.Wait(), ShouldBeNil)
+		})
+	})
+}
+
+func TestSafeGroup_Weighted(t *testing.T) {
+	Convey("Given a SafeGroup with a weight limit", t, func() {
+		var sg safegroup.Group
+		So(sg.SetLimit(10), ShouldBeNil)
+
+		Convey("GoWeighted should keep the total weight under the limit", func() {
+			var weight testutil.Gauge
+			for _, w := range []int64{6, 3, 5, 1, 10, 2, 4} {
+				err := sg.GoWeighted(w, func() error {
+					weight.Hold(w, 5*time.Millisecond)
+					return nil
+				})
+				So(err, ShouldBeNil)
+			}
+
+			So(sg.Wait(), ShouldBeNil)
+			So(weight.Peak(), ShouldBeLessThanOrEqualTo, 10)
+		})
+
+		Convey("GoWeighted should not wait for a weight above the limit", func() {
+			So(sg.GoWeighted(11, func() error { return nil }), ShouldEqual, group.ErrLimitExceeded)
+			So(sg.TryGo(func() {}), ShouldBeNil)
+			So(sg.Wait(), ShouldBeNil)
+		})
+
+		Convey("TryGoWeighted should fail if the weight does not fit", func() {
+			release := make(chan struct{})
+			So(sg.TryGoWeighted(8, func() error {
+				<-release
+				return nil
+			}), ShouldBeNil)
+			So(sg.InFlight(), ShouldEqual, 8)
+
+			So(sg.TryGoWeighted(3, func() error { return nil }), ShouldEqual, group.ErrLimitExceeded)
+			So(sg.TryGoWeighted(2, func() error { return nil }), ShouldBeNil)
+
+			close(release)
+			So(sg.Wait(), ShouldBeNil)
+			So(sg.InFlight(), ShouldEqual, 0)
 		})
 	})
 }
@@ -0,0 +1,32 @@
+// Package testutil provides helpers shared by the tests of this module.
+package testutil
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Gauge tracks an amount in use by concurrent tasks and the highest amount reached.
+// The zero value is ready to use.
+type Gauge struct {
+	current atomic.Int64
+	peak    atomic.Int64
+}
+
+// Hold adds n to the gauge for d, updating the peak.
+func (g *Gauge) Hold(n int64, d time.Duration) {
+	cur := g.current.Add(n)
+	for {
+		peak := g.peak.Load()
+		if cur <= peak || g.peak.CompareAndSwap(peak, cur) {
+			break
+		}
+	}
+	time.Sleep(d)
+	g.current.Add(-n)
+}
+
+// Peak returns the highest amount held at once.
+func (g *Gauge) Peak() int64 {
+	return g.peak.Load()
+}
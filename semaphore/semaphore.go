@@ -0,0 +1,159 @@
+// Package semaphore provides a weighted semaphore that waits in FIFO order,
+// honours contexts and can be resized while in use.
+package semaphore
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// Semaphore limits the total weight acquired at any time.
+// Capacity is handed out in FIFO order: a request waits while an earlier one waits,
+// so large requests are not starved by a stream of small ones.
+// The limit can be changed at any time: raising it admits waiting requests,
+// lowering it takes effect as acquired weight is released.
+// The zero value has no limit. A Semaphore must not be copied after first use.
+type Semaphore struct {
+	mu      sync.Mutex
+	limited bool
+	limit   int64
+	used    int64
+	// waiters holds the requests waiting for capacity, in arrival order.
+	waiters list.List
+}
+
+// New returns a Semaphore with the given limit; a negative limit means no limit.
+func New(limit int64) *Semaphore {
+	s := &Semaphore{}
+	s.Resize(limit)
+	return s
+}
+
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+// Limit returns the current limit, or -1 if there is none.
+func (s *Semaphore) Limit() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.limited {
+		return -1
+	}
+	return s.limit
+}
+
+// Acquired returns the weight acquired and not yet released.
+func (s *Semaphore) Acquired() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.used
+}
+
+// Resize sets the limit; a negative limit removes it.
+// Raising the limit admits waiting requests that fit.
+func (s *Semaphore) Resize(limit int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.limited = limit >= 0
+	s.limit = limit
+	s.notify()
+}
+
+// fits reports whether a weight of n can be acquired now.
+func (s *Semaphore) fits(n int64) bool {
+	return !s.limited || s.used+n <= s.limit
+}
+
+// TryAcquire acquires a weight of n without waiting and reports whether it succeeded.
+// It fails while other requests are waiting, even if n fits.
+func (s *Semaphore) TryAcquire(n int64) bool {
+	checkWeight(n)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.waiters.Len() > 0 || !s.fits(n) {
+		return false
+	}
+	s.used += n
+	return true
+}
+
+// Acquire waits for a weight of n until ctx is done, in which case it returns
+// context.Cause(ctx) without acquiring anything. A weight above the limit waits,
+// holding up the requests made after it, until the limit is raised.
+// Acquire panics if n is negative.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) error {
+	checkWeight(n)
+
+	s.mu.Lock()
+	if s.waiters.Len() == 0 && s.fits(n) {
+		s.used += n
+		s.mu.Unlock()
+		return nil
+	}
+
+	w := waiter{n: n, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		// The weight was handed out while ctx was being canceled: give it back.
+		s.used -= n
+	default:
+		s.waiters.Remove(elem)
+	}
+	// Either way the waiters behind this one may fit now.
+	s.notify()
+	return context.Cause(ctx)
+}
+
+// Release releases a weight of n acquired by Acquire or TryAcquire.
+// It panics if more weight is released than is acquired.
+func (s *Semaphore) Release(n int64) {
+	checkWeight(n)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.used < n {
+		panic("semaphore: released more than acquired")
+	}
+	s.used -= n
+	s.notify()
+}
+
+// notify hands out capacity to the waiters at the front of the queue.
+func (s *Semaphore) notify() {
+	for s.waiters.Len() > 0 {
+		elem := s.waiters.Front()
+		w := elem.Value.(waiter)
+		if !s.fits(w.n) {
+			return
+		}
+		s.waiters.Remove(elem)
+		s.used += w.n
+		close(w.ready)
+	}
+}
+
+func checkWeight(n int64) {
+	if n < 0 {
+		panic("semaphore: negative weight")
+	}
+}
@@ -0,0 +1,24 @@
+package semaphore_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/WhiCu/async/semaphore"
+)
+
+func BenchmarkSemaphore(b *testing.B) {
+	for _, limit := range []int64{1, 4, 16} {
+		b.Run(fmt.Sprintf("Limit_%d", limit), func(b *testing.B) {
+			s := semaphore.New(limit)
+			ctx := context.Background()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = s.Acquire(ctx, 1)
+					s.Release(1)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package semaphore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNew(t *testing.T) {
+	Convey("Given New", t, func() {
+		Convey("It should set the limit", func() {
+			s := New(2)
+			So(s.Limit(), ShouldEqual, 2)
+			So(s.TryAcquire(2), ShouldBeTrue)
+			So(s.TryAcquire(1), ShouldBeFalse)
+		})
+
+		Convey("It should accept a negative limit as no limit", func() {
+			So(New(-1).Limit(), ShouldEqual, -1)
+		})
+
+		Convey("Acquire and TryAcquire should panic on a negative weight", func() {
+			s := New(1)
+			So(func() { s.TryAcquire(-1) }, ShouldPanic)
+			So(func() { _ = s.Acquire(context.Background(), -1) }, ShouldPanic)
+		})
+	})
+}
+
+func TestSemaphore(t *testing.T) {
+	Convey("Given a zero Semaphore", t, func() {
+		var s Semaphore
+
+		Convey("It should have no limit", func() {
+			So(s.Limit(), ShouldEqual, -1)
+			for i := 0; i < 100; i++ {
+				So(s.TryAcquire(1), ShouldBeTrue)
+			}
+			So(s.Acquired(), ShouldEqual, 100)
+		})
+
+		Convey("When it is limited to one slot", func() {
+			s.Resize(1)
+			So(s.Acquire(context.Background(), 1), ShouldBeNil)
+
+			Convey("TryAcquire should fail", func() {
+				So(s.TryAcquire(1), ShouldBeFalse)
+			})
+
+			Convey("Waiters should be served in order", func() {
+				order := make(chan int, 3)
+				for i := 0; i < 3; i++ {
+					go func() {
+						_ = s.Acquire(context.Background(), 1)
+						order <- i
+						s.Release(1)
+					}()
+					waitWaiters(&s, i+1)
+				}
+
+				s.Release(1)
+				So(<-order, ShouldEqual, 0)
+				So(<-order, ShouldEqual, 1)
+				So(<-order, ShouldEqual, 2)
+			})
+
+			Convey("Raising the limit should admit waiters", func() {
+				acquired := make(chan struct{})
+				go func() {
+					_ = s.Acquire(context.Background(), 1)
+					close(acquired)
+				}()
+				waitWaiters(&s, 1)
+
+				s.Resize(2)
+				<-acquired
+				So(s.Acquired(), ShouldEqual, 2)
+			})
+
+			Convey("Lowering the limit should wait for releases", func() {
+				s.Resize(3)
+				So(s.TryAcquire(1), ShouldBeTrue)
+				So(s.TryAcquire(1), ShouldBeTrue)
+
+				s.Resize(1)
+				s.Release(1)
+				So(s.TryAcquire(1), ShouldBeFalse)
+				s.Release(1)
+				So(s.TryAcquire(1), ShouldBeFalse)
+				s.Release(1)
+				So(s.TryAcquire(1), ShouldBeTrue)
+			})
+
+			Convey("Acquire should return the cause when ctx is canceled", func() {
+				cause := errors.New("stop")
+				ctx, cancel := context.WithCancelCause(context.Background())
+				time.AfterFunc(10*time.Millisecond, func() { cancel(cause) })
+
+				So(s.Acquire(ctx, 1), ShouldEqual, cause)
+				So(s.Acquired(), ShouldEqual, 1)
+
+				s.Release(1)
+				So(s.TryAcquire(1), ShouldBeTrue)
+			})
+
+			Convey("A large request should not be starved by small ones", func() {
+				s.Resize(4)
+				acquired := make(chan struct{})
+				go func() {
+					_ = s.Acquire(context.Background(), 4)
+					close(acquired)
+				}()
+				waitWaiters(&s, 1)
+
+				So(s.TryAcquire(1), ShouldBeFalse)
+				s.Release(1)
+				<-acquired
+				So(s.Acquired(), ShouldEqual, 4)
+			})
+
+			Convey("Canceling the first waiter should admit the next ones", func() {
+				s.Resize(2)
+				ctx, cancel := context.WithCancel(context.Background())
+				failed := make(chan error)
+				go func() { failed <- s.Acquire(ctx, 2) }()
+				waitWaiters(&s, 1)
+
+				acquired := make(chan struct{})
+				go func() {
+					_ = s.Acquire(context.Background(), 1)
+					close(acquired)
+				}()
+				waitWaiters(&s, 2)
+
+				cancel()
+				So(<-failed, ShouldEqual, context.Canceled)
+				<-acquired
+				So(s.Acquired(), ShouldEqual, 2)
+			})
+
+			Convey("Release should panic when nothing is acquired", func() {
+				s.Release(1)
+				So(func() { s.Release(1) }, ShouldPanic)
+			})
+		})
+	})
+}
+
+// waitWaiters waits until n goroutines wait in Acquire.
+func waitWaiters(s *Semaphore, n int) {
+	for {
+		s.mu.Lock()
+		l := s.waiters.Len()
+		s.mu.Unlock()
+		if l >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,41 @@
+package try
+
+import (
+	"sync/atomic"
+)
+
+// Capture defines how much of the stack is captured when a panic is recovered.
+// Capturing the full stack is by far the most expensive part of recovering a panic,
+// so code that expects many panics may want to capture less.
+type Capture int32
+
+const (
+	// CaptureDefault uses the global mode set by SetCapture.
+	CaptureDefault Capture = iota
+	// CaptureNone captures nothing; Callers and Stack of the PanicError are nil.
+	CaptureNone
+	// CaptureCallers captures only the program counters in Callers.
+	CaptureCallers
+	// CaptureFull captures the program counters and the formatted goroutine stack.
+	CaptureFull
+)
+
+var capture atomic.Int32
+
+// SetCapture sets the global Capture mode used by the Try functions and by
+// Triers without a mode of their own. The initial mode is CaptureFull;
+// CaptureDefault restores it. It is safe to call concurrently.
+func SetCapture(c Capture) {
+	capture.Store(int32(c))
+}
+
+// resolve replaces CaptureDefault with the global mode.
+func (c Capture) resolve() Capture {
+	if c == CaptureDefault {
+		c = Capture(capture.Load())
+	}
+	if c == CaptureDefault {
+		return CaptureFull
+	}
+	return c
+}
@@ -0,0 +1,56 @@
+package try
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCapture(t *testing.T) {
+	Convey("Given the global capture mode", t, func() {
+		defer SetCapture(CaptureDefault)
+
+		Convey("By default it should capture the callers and the stack", func() {
+			pe := Try(func() { panic("full") }).(*PanicError)
+			So(pe.Callers, ShouldNotBeEmpty)
+			So(pe.Stack, ShouldNotBeEmpty)
+		})
+
+		Convey("CaptureCallers should only capture the callers", func() {
+			SetCapture(CaptureCallers)
+			pe := Try(func() { panic("callers") }).(*PanicError)
+			So(pe.Callers, ShouldNotBeEmpty)
+			So(pe.Stack, ShouldBeNil)
+		})
+
+		Convey("CaptureNone should capture nothing", func() {
+			SetCapture(CaptureNone)
+			_, err := TryValue(func() int { panic("none") })
+			pe := err.(*PanicError)
+			So(pe.Value, ShouldEqual, "none")
+			So(pe.Callers, ShouldBeNil)
+			So(pe.Stack, ShouldBeNil)
+			So(pe.Frames(), ShouldBeNil)
+		})
+	})
+
+	Convey("Given a Trier with its own capture mode", t, func() {
+		defer SetCapture(CaptureDefault)
+		SetCapture(CaptureNone)
+
+		tr := &Trier[int]{}
+		tr.SetCapture(CaptureFull)
+
+		Convey("It should override the global mode", func() {
+			pe := tr.Try(func() { panic("trier") }).(*PanicError)
+			So(pe.Callers, ShouldNotBeEmpty)
+			So(pe.Stack, ShouldNotBeEmpty)
+		})
+
+		Convey("CaptureDefault should follow the global mode again", func() {
+			tr.SetCapture(CaptureDefault)
+			pe := tr.Try(func() { panic("trier") }).(*PanicError)
+			So(pe.Callers, ShouldBeNil)
+		})
+	})
+}
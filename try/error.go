@@ -9,9 +9,17 @@ import (
 
 var (
 	// SkipFrames is the number of frames to skip when creating a panic error.
+	//
+	// Deprecated: The frames to skip are computed internally and SkipFrames is ignored.
 	SkipFrames = 3
 )
 
+// skipFrames is the number of frames between runtime.Callers and the panic:
+// callers, newPanicError, its caller (NewPanicError or recovered)
+// and the deferred function that called recover.
+// The frames of the panic machinery itself are dropped by Frames.
+const skipFrames = 4
+
 type PanicError struct {
 	Value   any
 	Callers []uintptr
@@ -33,14 +41,58 @@ func (e *PanicError) Unwrap() error {
 // It includes the calling stack frames and the current goroutine stack trace.
 // It is safe to call on multiple goroutines concurrently.
 // It can be unwrapped with errors.Unwrap to get the original error.
+// What is captured depends on the global Capture mode.
+// It must be called directly by the deferred function that recovered value.
 func NewPanicError(value any) error {
-	var callers [64]uintptr
-	n := runtime.Callers(SkipFrames, callers[:])
-	return &PanicError{
-		Value:   value,
-		Callers: callers[:n],
-		Stack:   debug.Stack(),
+	return newPanicError(value, CaptureDefault)
+}
+
+func newPanicError(value any, c Capture) *PanicError {
+	pe := &PanicError{
+		Value: value,
+	}
+
+	switch c.resolve() {
+	case CaptureNone:
+	case CaptureCallers:
+		pe.Callers = callers()
+	default:
+		pe.Callers = callers()
+		pe.Stack = debug.Stack()
+	}
+	return pe
+}
+
+// callers returns the program counters of the stack that called newPanicError.
+func callers() []uintptr {
+	var pcs [64]uintptr
+	n := runtime.Callers(skipFrames+1, pcs[:])
+	return pcs[:n]
+}
+
+// recovered converts a value returned by recover to an error, capturing
+// the stack according to c, and reports it to the panic handler.
+// It must be called directly by the deferred function that recovered value.
+// A value re-raised by Repanic, or a *PanicError raised with panic, is returned
+// as the original *PanicError and not reported twice.
+// A value rejected by the filters of opts is raised again with panic.
+func recovered(value any, c Capture, opts []Option) *PanicError {
+	pe := recall(value)
+	if !accepts(opts, value) {
+		if pe != nil {
+			remember(pe)
+		}
+		panic(value)
+	}
+	if pe != nil {
+		return pe
+	}
+	if pe, ok := value.(*PanicError); ok {
+		return pe
 	}
+	pe = newPanicError(value, c)
+	report(pe)
+	return pe
 }
 
 func AsPanicError(err error) bool {
@@ -0,0 +1,76 @@
+package try
+
+import (
+	"errors"
+	"runtime"
+)
+
+// Option configures which panics are recovered by the Try functions, Run,
+// Trier and SyncTrier.
+type Option func(*options)
+
+type options struct {
+	filters []func(any) bool
+}
+
+// WithFilter only recovers panics whose value is accepted by filter.
+// Any other panic is raised again as if it had never been recovered,
+// so it still crashes the process unless recovered further up the stack.
+// When several filters are given, a panic must be accepted by all of them.
+func WithFilter(filter func(any) bool) Option {
+	return func(o *options) {
+		o.filters = append(o.filters, filter)
+	}
+}
+
+// accepts reports whether the panic value is accepted by the filters of opts.
+// A *PanicError raised with panic is judged by its original value.
+func accepts(opts []Option, value any) bool {
+	if len(opts) == 0 {
+		return true
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if pe, ok := value.(*PanicError); ok {
+		value = pe.Value
+	}
+	for _, filter := range o.filters {
+		if !filter(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsRuntimeError reports whether v is or wraps a runtime.Error,
+// such as a nil map write, an index out of range or a nil pointer dereference.
+// Such panics are usually programming errors.
+func IsRuntimeError(v any) bool {
+	err, ok := v.(error)
+	if !ok {
+		return false
+	}
+	var re runtime.Error
+	return errors.As(err, &re)
+}
+
+// IsUserValue reports whether v was raised by a call to panic in user code,
+// that is, whether it is not a runtime.Error.
+func IsUserValue(v any) bool {
+	return !IsRuntimeError(v)
+}
+
+// IsError reports whether v is an error.
+func IsError(v any) bool {
+	_, ok := v.(error)
+	return ok
+}
+
+// IsNonError reports whether v is not an error, for example a string passed to panic.
+func IsNonError(v any) bool {
+	return !IsError(v)
+}
@@ -0,0 +1,114 @@
+package try
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// recoverAll calls f and returns the value of a panic that escaped it.
+func recoverAll(f func()) (r any) {
+	defer func() {
+		r = recover()
+	}()
+	f()
+	return nil
+}
+
+func runtimeError() {
+	var m map[string]int
+	m["boom"] = 1
+}
+
+func TestClassifiers(t *testing.T) {
+	Convey("Given panic values", t, func() {
+		var re error
+		_ = recoverAll(func() {
+			defer func() { re = recover().(error) }()
+			runtimeError()
+		})
+		userErr := errors.New("user")
+
+		Convey("IsRuntimeError should accept runtime errors only", func() {
+			So(IsRuntimeError(re), ShouldBeTrue)
+			So(IsRuntimeError(fmt.Errorf("wrapped: %w", re)), ShouldBeTrue)
+			So(IsRuntimeError(userErr), ShouldBeFalse)
+			So(IsRuntimeError("boom"), ShouldBeFalse)
+			So(IsUserValue(re), ShouldBeFalse)
+			So(IsUserValue("boom"), ShouldBeTrue)
+		})
+
+		Convey("IsError should accept errors only", func() {
+			So(IsError(userErr), ShouldBeTrue)
+			So(IsError(re), ShouldBeTrue)
+			So(IsError("boom"), ShouldBeFalse)
+			So(IsNonError("boom"), ShouldBeTrue)
+			So(IsNonError(userErr), ShouldBeFalse)
+		})
+	})
+}
+
+func TestWithFilter(t *testing.T) {
+	Convey("Given a filter accepting only user values", t, func() {
+		filter := WithFilter(IsUserValue)
+
+		Convey("It should recover an accepted panic", func() {
+			err := Try(func() { panic("boom") }, filter)
+			So(AsPanicError(err), ShouldBeTrue)
+		})
+
+		Convey("It should raise a rejected panic again", func() {
+			r := recoverAll(func() {
+				_ = TryErr(func() error {
+					runtimeError()
+					return nil
+				}, filter)
+			})
+			So(IsRuntimeError(r), ShouldBeTrue)
+		})
+
+		Convey("It should require every filter to accept the panic", func() {
+			r := recoverAll(func() {
+				_, _ = TryValue(func() int { panic("boom") }, filter, WithFilter(IsError))
+			})
+			So(r, ShouldEqual, "boom")
+		})
+
+		Convey("It should judge a repanicked PanicError by its value", func() {
+			pe := &PanicError{Value: "boom"}
+			_, err := TryValueErr(func() (int, error) { panic(pe) }, WithFilter(IsNonError))
+			So(err, ShouldEqual, pe)
+		})
+
+		Convey("Run should not call done for a rejected panic", func() {
+			called := false
+			r := recoverAll(func() {
+				Run(func() error {
+					runtimeError()
+					return nil
+				}, func(error) { called = true }, filter)
+			})
+			So(IsRuntimeError(r), ShouldBeTrue)
+			So(called, ShouldBeFalse)
+		})
+
+		Convey("A Trier should apply its options", func() {
+			var tr TrierAny
+			tr.SetOptions(WithFilter(IsError))
+
+			So(tr.Try(func() { panic(errors.New("fail")) }), ShouldNotBeNil)
+			So(recoverAll(func() { _ = tr.Try(func() { panic("boom") }) }), ShouldEqual, "boom")
+			So(tr.Worked(), ShouldBeFalse)
+		})
+
+		Convey("A SyncTrier should apply its options", func() {
+			var tr SyncTrier[any]
+			tr.SetOptions(filter)
+
+			So(recoverAll(func() { _ = tr.Try(runtimeError) }), ShouldNotBeNil)
+			So(tr.Count(), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,83 @@
+package try
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// pkgPrefix is the prefix of the names of the functions in this package.
+var pkgPrefix = func() string {
+	name := runtime.FuncForPC(reflect.ValueOf(Try).Pointer()).Name()
+	return strings.TrimSuffix(name, "Try")
+}()
+
+// internalFrame reports whether a frame belongs to the runtime or to this package.
+func internalFrame(f runtime.Frame) bool {
+	return strings.HasPrefix(f.Function, "runtime.") || strings.HasPrefix(f.Function, pkgPrefix)
+}
+
+// Frames returns the symbolized frames of Callers, starting at the function that panicked.
+// Frames belonging to the runtime and to this package are dropped.
+func (e *PanicError) Frames() []runtime.Frame {
+	if len(e.Callers) == 0 {
+		return nil
+	}
+
+	var frames []runtime.Frame
+	it := runtime.CallersFrames(e.Callers)
+	for {
+		f, more := it.Next()
+		if !internalFrame(f) {
+			frames = append(frames, f)
+		}
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// stack returns Frames as lines of the form "function file:line".
+func (e *PanicError) stack() []string {
+	frames := e.Frames()
+	lines := make([]string, len(frames))
+	for i, f := range frames {
+		lines[i] = f.Function + " " + f.File + ":" + strconv.Itoa(f.Line)
+	}
+	return lines
+}
+
+// Format implements fmt.Formatter.
+// The %+v verb prints the error followed by the stack returned by Frames;
+// %v and %s print the same as Error and %q prints it quoted.
+func (e *PanicError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		_, _ = io.WriteString(s, e.Error())
+		if s.Flag('+') {
+			for _, f := range e.Frames() {
+				_, _ = fmt.Fprintf(s, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+			}
+		}
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.Error())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(*try.PanicError=%s)", verb, e.Error())
+	}
+}
+
+// LogValue implements slog.LogValuer.
+// It logs the panic value and the stack returned by Frames.
+func (e *PanicError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("value", fmt.Sprint(e.Value)),
+		slog.Any("stack", e.stack()),
+	)
+}
@@ -0,0 +1,68 @@
+package try_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/WhiCu/async/try"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func explode() {
+	panic("kaboom")
+}
+
+func TestPanicErrorFormat(t *testing.T) {
+	Convey("Given a PanicError from a panicking function", t, func() {
+		err := try.Try(explode)
+		pe := err.(*try.PanicError)
+
+		Convey("Frames should start at the function that panicked", func() {
+			frames := pe.Frames()
+			So(frames, ShouldNotBeEmpty)
+			So(frames[0].Function, ShouldEndWith, "try_test.explode")
+
+			for _, f := range frames {
+				So(f.Function, ShouldNotStartWith, "runtime.")
+				So(f.Function, ShouldNotStartWith, "github.com/WhiCu/async/try.")
+			}
+		})
+
+		Convey("%v and %s should print the error", func() {
+			So(fmt.Sprintf("%v", pe), ShouldEqual, "panic: kaboom")
+			So(fmt.Sprintf("%s", pe), ShouldEqual, "panic: kaboom")
+			So(fmt.Sprintf("%q", pe), ShouldEqual, `"panic: kaboom"`)
+		})
+
+		Convey("%+v should print the stack", func() {
+			out := fmt.Sprintf("%+v", pe)
+			lines := strings.Split(out, "\n")
+
+			So(lines[0], ShouldEqual, "panic: kaboom")
+			So(lines[1], ShouldEndWith, "try_test.explode")
+			So(lines[2], ShouldContainSubstring, "format_test.go:")
+			So(out, ShouldNotContainSubstring, "runtime.gopanic")
+		})
+
+		Convey("It should log the value and the stack with slog", func() {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			logger.Error("task failed", "panic", pe)
+
+			var entry struct {
+				Panic struct {
+					Value string   `json:"value"`
+					Stack []string `json:"stack"`
+				} `json:"panic"`
+			}
+			So(json.Unmarshal(buf.Bytes(), &entry), ShouldBeNil)
+			So(entry.Panic.Value, ShouldEqual, "kaboom")
+			So(entry.Panic.Stack, ShouldNotBeEmpty)
+			So(entry.Panic.Stack[0], ShouldContainSubstring, "try_test.explode")
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package try
+
+import (
+	"errors"
+	"runtime"
+)
+
+// ErrGoexit is reported by Run when f calls runtime.Goexit,
+// for example through testing.T.FailNow.
+var ErrGoexit = errors.New("try: runtime.Goexit called")
+
+// Run calls f and then done with the error returned by f, its panic as a *PanicError,
+// or ErrGoexit if f called runtime.Goexit. In the last case done runs while
+// the goroutine is exiting and the goroutine keeps exiting once done returns,
+// so done is the only place where the outcome can be observed.
+// A panic rejected by the filters of opts is not passed to done and keeps panicking.
+func Run(f func() error, done func(error), opts ...Option) {
+	// If TryErr never returns, f called runtime.Goexit or TryErr raised
+	// a rejected panic again: unlike the panic, Goexit cannot be recovered,
+	// but deferred calls still run.
+	err := ErrGoexit
+	defer func() {
+		if err == ErrGoexit {
+			if r := recover(); r != nil {
+				panic(r)
+			}
+		}
+		done(err)
+	}()
+	err = TryErr(f, opts...)
+}
+
+// Repanic re-raises the failure held by err, if any.
+// If err is or wraps a *PanicError, Repanic panics with its original value,
+// so a plain recover further up the stack sees the value f panicked with;
+// a Try function recovering it on the same goroutine returns the PanicError
+// unchanged, with the stack captured by the first recovery.
+// If err is ErrGoexit, Repanic calls runtime.Goexit.
+// Any other non-nil error is raised with panic as is.
+func Repanic(err error) {
+	if err == nil {
+		return
+	}
+
+	var pe *PanicError
+	switch {
+	case errors.As(err, &pe):
+		remember(pe)
+		panic(pe.Value)
+	case errors.Is(err, ErrGoexit):
+		runtime.Goexit()
+	default:
+		panic(err)
+	}
+}
@@ -0,0 +1,89 @@
+package try
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// runAsync calls Run with f in a new goroutine and returns the reported error.
+func runAsync(f func() error) error {
+	c := make(chan error, 1)
+	go Run(f, func(err error) { c <- err })
+	return <-c
+}
+
+func TestRun(t *testing.T) {
+	Convey("Given Run", t, func() {
+		Convey("It should report the error of f", func() {
+			testErr := errors.New("fail")
+			So(runAsync(func() error { return testErr }), ShouldEqual, testErr)
+			So(runAsync(func() error { return nil }), ShouldBeNil)
+		})
+
+		Convey("It should report a panic as a PanicError", func() {
+			err := runAsync(func() error { panic("boom") })
+			So(AsPanicError(err), ShouldBeTrue)
+		})
+
+		Convey("It should report runtime.Goexit as ErrGoexit", func() {
+			err := runAsync(func() error {
+				runtime.Goexit()
+				return nil
+			})
+			So(err, ShouldEqual, ErrGoexit)
+		})
+	})
+}
+
+func TestRepanic(t *testing.T) {
+	Convey("Given Repanic", t, func() {
+		Convey("It should do nothing for nil", func() {
+			So(func() { Repanic(nil) }, ShouldNotPanic)
+		})
+
+		Convey("It should re-raise a PanicError unchanged", func() {
+			first := Try(func() { panic("boom") })
+			second := Try(func() { Repanic(first) })
+
+			So(second, ShouldEqual, first)
+			So(second.(*PanicError).Value, ShouldEqual, "boom")
+		})
+
+		Convey("It should panic with the original value", func() {
+			first := Try(func() { panic(http.ErrAbortHandler) })
+			r := recoverAll(func() { Repanic(first) })
+
+			So(r == http.ErrAbortHandler, ShouldBeTrue)
+		})
+
+		Convey("It should keep the original PanicError through an incomparable value", func() {
+			first := Try(func() { panic([]int{1}) })
+
+			So(Try(func() { Repanic(first) }), ShouldEqual, first)
+		})
+
+		Convey("It should re-raise a wrapped PanicError", func() {
+			first := Try(func() { panic("boom") })
+			wrapped := errors.Join(errors.New("context"), first)
+
+			So(Try(func() { Repanic(wrapped) }), ShouldEqual, first)
+		})
+
+		Convey("It should panic with any other error", func() {
+			testErr := errors.New("fail")
+			So(func() { Repanic(testErr) }, ShouldPanicWith, testErr)
+		})
+
+		Convey("It should call runtime.Goexit for ErrGoexit", func() {
+			err := runAsync(func() error {
+				Repanic(ErrGoexit)
+				return nil
+			})
+			So(err, ShouldEqual, ErrGoexit)
+		})
+	})
+}
@@ -0,0 +1,26 @@
+package try
+
+import (
+	"sync/atomic"
+)
+
+var panicHandler atomic.Pointer[func(*PanicError)]
+
+// SetPanicHandler sets the function called with every panic recovered by this package,
+// including the ones recovered on behalf of other packages and never returned to a caller.
+// The handler runs on the panicking goroutine and must not panic.
+// A nil handler disables reporting. It is safe to call concurrently.
+func SetPanicHandler(h func(*PanicError)) {
+	if h == nil {
+		panicHandler.Store(nil)
+		return
+	}
+	panicHandler.Store(&h)
+}
+
+// report passes pe to the panic handler, if one is set.
+func report(pe *PanicError) {
+	if h := panicHandler.Load(); h != nil {
+		(*h)(pe)
+	}
+}
@@ -0,0 +1,68 @@
+package try
+
+import (
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPanicHandler(t *testing.T) {
+	Convey("Given a panic handler", t, func() {
+		var mu sync.Mutex
+		var reported []*PanicError
+		SetPanicHandler(func(pe *PanicError) {
+			mu.Lock()
+			reported = append(reported, pe)
+			mu.Unlock()
+		})
+		defer SetPanicHandler(nil)
+
+		Convey("It should receive the panics recovered by the Try functions", func() {
+			err := Try(func() { panic("one") })
+			_, _ = TryValue(func() int { panic("two") })
+			_ = TryErr(func() error { panic("three") })
+			_, _ = TryValueErr(func() (int, error) { panic("four") })
+
+			So(reported, ShouldHaveLength, 4)
+			So(reported[0], ShouldEqual, err)
+			So(reported[0].Value, ShouldEqual, "one")
+			So(reported[3].Value, ShouldEqual, "four")
+		})
+
+		Convey("It should receive the panics recovered by a Trier", func() {
+			tr := &Trier[int]{}
+			err := tr.Try(func() { panic("trier") })
+
+			So(reported, ShouldHaveLength, 1)
+			So(reported[0], ShouldEqual, err)
+		})
+
+		Convey("It should not be called without a panic", func() {
+			_ = Try(func() {})
+			_ = TryErr(func() error { return nil })
+
+			So(reported, ShouldBeEmpty)
+		})
+
+		Convey("It should be safe to replace concurrently", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Go(func() {
+					SetPanicHandler(func(*PanicError) {})
+					_ = Try(func() { panic("race") })
+				})
+			}
+			wg.Wait()
+		})
+	})
+
+	Convey("Given no panic handler", t, func() {
+		SetPanicHandler(nil)
+
+		Convey("Panics should still be returned", func() {
+			err := Try(func() { panic("unreported") })
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,74 @@
+package try
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// repanic is a PanicError re-raised by Repanic on the goroutine gid.
+type repanic struct {
+	gid uint64
+	pe  *PanicError
+}
+
+// repanics remembers the PanicErrors re-raised by Repanic, so that recovered
+// can return them with their original stack although Repanic panics with
+// the bare value. Slots are chosen by goroutine id and overwritten freely:
+// a lost slot only means a fresh PanicError is captured instead.
+var (
+	repanics       [64]atomic.Pointer[repanic]
+	repanicPending atomic.Int64
+)
+
+// remember stores pe as re-raised by the current goroutine.
+func remember(pe *PanicError) {
+	gid := goid()
+	if old := repanics[gid%uint64(len(repanics))].Swap(&repanic{gid: gid, pe: pe}); old == nil {
+		repanicPending.Add(1)
+	}
+}
+
+// recall returns the PanicError re-raised by Repanic on the current goroutine
+// if its value is value, and forgets it.
+func recall(value any) *PanicError {
+	if repanicPending.Load() == 0 {
+		return nil
+	}
+	gid := goid()
+	slot := &repanics[gid%uint64(len(repanics))]
+	r := slot.Load()
+	if r == nil || r.gid != gid || !slot.CompareAndSwap(r, nil) {
+		return nil
+	}
+	repanicPending.Add(-1)
+	if !sameValue(r.pe.Value, value) {
+		return nil
+	}
+	return r.pe
+}
+
+// sameValue reports whether a and b are equal.
+// Incomparable values, such as slices, are only compared by type.
+func sameValue(a, b any) (same bool) {
+	defer func() {
+		if recover() != nil {
+			same = reflect.TypeOf(a) == reflect.TypeOf(b)
+		}
+	}()
+	return a == b
+}
+
+// goid returns the id of the current goroutine, parsed from the header of its stack.
+func goid() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,40 @@
+package try
+
+import "sync/atomic"
+
+// OptionList holds options that can be replaced while other goroutines read them.
+// The zero value holds no options.
+type OptionList struct {
+	opts atomic.Pointer[[]Option]
+}
+
+// Store replaces the options in the list.
+func (l *OptionList) Store(opts ...Option) {
+	l.opts.Store(&opts)
+}
+
+// Load returns the options stored last.
+func (l *OptionList) Load() []Option {
+	if opts := l.opts.Load(); opts != nil {
+		return *opts
+	}
+	return nil
+}
+
+// settings holds the capture mode and options shared by Trier and SyncTrier.
+type settings struct {
+	capture atomic.Int32
+	opts    OptionList
+}
+
+// SetCapture sets how much of the stack is captured for the panics recovered by the trier.
+// CaptureDefault, the initial mode, follows the global mode set by SetCapture.
+func (s *settings) SetCapture(c Capture) {
+	s.capture.Store(int32(c))
+}
+
+// SetOptions sets the options applied to the functions run by the trier,
+// replacing the ones set before.
+func (s *settings) SetOptions(opts ...Option) {
+	s.opts.Store(opts...)
+}
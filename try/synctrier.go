@@ -0,0 +1,130 @@
+package try
+
+import "sync"
+
+// DefaultHistory is the number of panics a SyncTrier keeps unless SetHistory is called.
+const DefaultHistory = 16
+
+// SyncTrier is a Trier that can be used by many goroutines at once.
+// Instead of the outcome of the last call, it records every panic it recovers:
+// Count reports how many there were and Panics returns the most recent ones.
+// The zero value is ready to use.
+type SyncTrier[T any] struct {
+	mu      sync.Mutex
+	count   int
+	limit   int
+	history []*PanicError
+	// next is the position in history where the next panic is stored
+	// once history is full.
+	next int
+
+	settings
+}
+
+// SetHistory sets the number of panics kept for Panics and clears the current history.
+// A limit less than 1 keeps DefaultHistory panics. Count is not affected.
+func (t *SyncTrier[T]) SetHistory(limit int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.limit = limit
+	t.history = nil
+	t.next = 0
+}
+
+func (t *SyncTrier[T]) record(pe *PanicError) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	limit := t.limit
+	if limit < 1 {
+		limit = DefaultHistory
+	}
+
+	t.count++
+	if len(t.history) < limit {
+		t.history = append(t.history, pe)
+		return
+	}
+	t.history[t.next] = pe
+	t.next = (t.next + 1) % len(t.history)
+}
+
+// recover captures any panic that occurs, records it and stores it in err.
+func (t *SyncTrier[T]) recover(err *error) {
+	if r := recover(); r != nil {
+		pe := recovered(r, Capture(t.capture.Load()), t.opts.Load())
+		t.record(pe)
+		*err = pe
+	}
+}
+
+// Try executes function f and returns an error if it panicked.
+func (t *SyncTrier[T]) Try(f func()) (err error) {
+	defer t.recover(&err)
+	f()
+	return nil
+}
+
+// TryValue executes function f and returns its result along with any panic as an error.
+func (t *SyncTrier[T]) TryValue(f func() T) (v T, err error) {
+	defer t.recover(&err)
+	return f(), nil
+}
+
+// TryErr executes function f and returns its error, or a panic error if f panicked.
+func (t *SyncTrier[T]) TryErr(f func() error) (err error) {
+	defer t.recover(&err)
+	return f()
+}
+
+// TryValueErr executes function f and returns its value and error, or a panic error if f panicked.
+func (t *SyncTrier[T]) TryValueErr(f func() (T, error)) (v T, err error) {
+	defer t.recover(&err)
+	return f()
+}
+
+// Count returns the number of panics recovered since the last Clean.
+func (t *SyncTrier[T]) Count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count
+}
+
+// Panics returns the most recent recovered panics, oldest first.
+func (t *SyncTrier[T]) Panics() []*PanicError {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	panics := make([]*PanicError, 0, len(t.history))
+	panics = append(panics, t.history[t.next:]...)
+	panics = append(panics, t.history[:t.next]...)
+	return panics
+}
+
+// Worked returns true if any function panicked since the last Clean.
+func (t *SyncTrier[T]) Worked() bool {
+	return t.Count() > 0
+}
+
+// Value returns the value of the most recent panic, or nil if no panic occurred.
+func (t *SyncTrier[T]) Value() any {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.history) == 0 {
+		return nil
+	}
+	last := (t.next + len(t.history) - 1) % len(t.history)
+	return t.history[last].Value
+}
+
+// Clean resets the count and the history of recovered panics.
+func (t *SyncTrier[T]) Clean() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.count = 0
+	t.history = nil
+	t.next = 0
+}
@@ -0,0 +1,93 @@
+package try
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSyncTrier(t *testing.T) {
+	Convey("Given a new SyncTrier", t, func() {
+		tr := &SyncTrier[int]{}
+
+		Convey("When running functions that do not panic", func() {
+			v, err := tr.TryValue(func() int { return 1 })
+			So(v, ShouldEqual, 1)
+			So(err, ShouldBeNil)
+
+			err = tr.TryErr(func() error { return errors.New("fail") })
+			So(err.Error(), ShouldEqual, "fail")
+
+			Convey("Then it should not record a panic", func() {
+				So(tr.Worked(), ShouldBeFalse)
+				So(tr.Count(), ShouldEqual, 0)
+				So(tr.Value(), ShouldBeNil)
+				So(tr.Panics(), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When running functions that panic", func() {
+			err1 := tr.Try(func() { panic("one") })
+			_, err2 := tr.TryValueErr(func() (int, error) { panic("two") })
+
+			Convey("Then it should return and record each panic", func() {
+				So(err1.Error(), ShouldContainSubstring, "one")
+				So(err2.Error(), ShouldContainSubstring, "two")
+				So(tr.Worked(), ShouldBeTrue)
+				So(tr.Count(), ShouldEqual, 2)
+				So(tr.Value(), ShouldEqual, "two")
+				So(tr.Panics(), ShouldResemble, []*PanicError{err1.(*PanicError), err2.(*PanicError)})
+			})
+
+			Convey("And when Clean is called", func() {
+				tr.Clean()
+
+				Convey("Then the history should be empty", func() {
+					So(tr.Worked(), ShouldBeFalse)
+					So(tr.Panics(), ShouldBeEmpty)
+				})
+			})
+		})
+
+		Convey("When more panics occur than the history holds", func() {
+			tr.SetHistory(3)
+			for _, v := range []string{"a", "b", "c", "d", "e"} {
+				_ = tr.Try(func() { panic(v) })
+			}
+
+			Convey("Then it should keep the most recent ones", func() {
+				So(tr.Count(), ShouldEqual, 5)
+				So(tr.Value(), ShouldEqual, "e")
+
+				var values []any
+				for _, pe := range tr.Panics() {
+					values = append(values, pe.Value)
+				}
+				So(values, ShouldResemble, []any{"c", "d", "e"})
+			})
+		})
+
+		Convey("When used from many goroutines", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 100; i++ {
+				wg.Go(func() {
+					_ = tr.Try(func() {
+						if i%2 == 0 {
+							panic(i)
+						}
+					})
+					_ = tr.Worked()
+					_ = tr.Value()
+				})
+			}
+			wg.Wait()
+
+			Convey("Then it should count every panic", func() {
+				So(tr.Count(), ShouldEqual, 50)
+				So(tr.Panics(), ShouldHaveLength, DefaultHistory)
+			})
+		})
+	})
+}
@@ -1,4 +1,4 @@
-// Package panics provides utilities for safely handling panics in Go programs.
+// Package try provides utilities for safely handling panics in Go programs.
 // It offers a Trier type that can execute functions and capture any panics that occur,
 // converting them to errors for easier handling in Go's error-based control flow.
 package try
@@ -12,25 +12,21 @@ type (
 	TrierAny = Trier[any]
 )
 
-// Trier provides thread-safe panic handling for functions.
+// Trier provides panic handling for functions.
 // It can execute functions and capture any panics that occur,
-// converting them to errors
-// Don't use pointer as Trier is not thread-safe
-// Trier is not thread-safe
+// converting them to errors.
+// A Trier only remembers the outcome of its last call, so concurrent calls
+// overwrite each other's state; use SyncTrier to share one between goroutines.
 type Trier[T any] struct {
-	value atomic.Pointer[any]
+	panic atomic.Pointer[PanicError]
+	settings
 	// worked atomic.Bool
 }
 
-// storePanic stores the panic value atomically with a stable heap-allocated address.
-func (t *Trier[T]) storePanic(r any) {
-	t.value.Store(&r)
-}
-
-// recover captures any panic that occurs and stores it atomically.
+// recover captures any panic that occurs, reports it to the panic handler and stores it atomically.
 func (t *Trier[T]) recover() {
 	if r := recover(); r != nil {
-		t.storePanic(r)
+		t.panic.Store(recovered(r, Capture(t.capture.Load()), t.opts.Load()))
 	}
 }
 
@@ -87,35 +83,35 @@ func (t *Trier[T]) Repanic() {
 
 // Value returns the stored panic value, or nil if no panic occurred.
 func (t *Trier[T]) Value() any {
-	if t.value.Load() == nil {
-		return nil
+	if pe := t.panic.Load(); pe != nil {
+		return pe.Value
 	}
-	return *t.value.Load()
+	return nil
 }
 
-// PanicAsError returns the stored panic value as an error, or nil if no panic occurred.
+// PanicAsError returns the stored panic as an error, or nil if no panic occurred.
 func (t *Trier[T]) PanicAsError() error {
-	if t.Value() == nil {
-		return nil
+	if pe := t.panic.Load(); pe != nil {
+		return pe
 	}
-	return NewPanicError(t.Value())
+	return nil
 }
 
 // сhangeErrorIfPanic returns the original error if no panic occurred, or converts the panic to an error.
 func (t *Trier[T]) changeErrorIfPanic(err error) error {
-	if t.Value() == nil {
-		return err
+	if pe := t.panic.Load(); pe != nil {
+		return pe
 	}
-	return NewPanicError(t.Value())
+	return err
 }
 
 // Clean resets the Trier state, clearing any stored panic information.
 func (t *Trier[T]) Clean() {
-	t.value.Store(nil)
+	t.panic.Store(nil)
 }
 
 // Worked returns true if the last executed function panicked.
 // It uses CompareAndSwap to check if a panic value is stored.
 func (t *Trier[T]) Worked() bool {
-	return !t.value.CompareAndSwap(nil, nil)
+	return !t.panic.CompareAndSwap(nil, nil)
 }
@@ -78,3 +78,12 @@ func BenchmarkTrier_TryValueErr_Panic(b *testing.B) {
 		_, _ = t.TryValueErr(f)
 	}
 }
+
+func BenchmarkTrier_Try_Panic_CaptureNone(b *testing.B) {
+	t := &try.Trier[int]{}
+	t.SetCapture(try.CaptureNone)
+	f := func() { panic("boom") }
+	for b.Loop() {
+		_ = t.Try(f)
+	}
+}
@@ -1,9 +1,10 @@
 package try
 
-func Try(f func()) (err error) {
+// Try executes f and returns its panic as an error, if any.
+func Try(f func(), opts ...Option) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = NewPanicError(r)
+			err = recovered(r, CaptureDefault, opts)
 		}
 	}()
 	f()
@@ -11,10 +12,10 @@ func Try(f func()) (err error) {
 }
 
 // TryValue executes f and returns its result along with a panic as an error, if any.
-func TryValue[T any](f func() T) (v T, err error) {
+func TryValue[T any](f func() T, opts ...Option) (v T, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = NewPanicError(r)
+			err = recovered(r, CaptureDefault, opts)
 		}
 	}()
 	v = f()
@@ -22,10 +23,10 @@ func TryValue[T any](f func() T) (v T, err error) {
 }
 
 // TryErr executes f and returns its error, or a panic as an error, if any.
-func TryErr(f func() error) (err error) {
+func TryErr(f func() error, opts ...Option) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = NewPanicError(r)
+			err = recovered(r, CaptureDefault, opts)
 		}
 	}()
 	err = f()
@@ -33,10 +34,10 @@ func TryErr(f func() error) (err error) {
 }
 
 // TryValueErr executes f and returns its value, error, and a panic as an error, if any.
-func TryValueErr[T any](f func() (T, error)) (v T, err error) {
+func TryValueErr[T any](f func() (T, error), opts ...Option) (v T, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = NewPanicError(r)
+			err = recovered(r, CaptureDefault, opts)
 		}
 	}()
 	v, err = f()
@@ -71,3 +71,24 @@ func BenchmarkTryValueErr_Panic(b *testing.B) {
 		_, _ = try.TryValueErr(f)
 	}
 }
+
+func BenchmarkTry_Panic_Capture(b *testing.B) {
+	modes := []struct {
+		name string
+		mode try.Capture
+	}{
+		{"None", try.CaptureNone},
+		{"Callers", try.CaptureCallers},
+		{"Full", try.CaptureFull},
+	}
+	f := func() { panic("boom") }
+	for _, m := range modes {
+		b.Run(m.name, func(b *testing.B) {
+			try.SetCapture(m.mode)
+			defer try.SetCapture(try.CaptureDefault)
+			for b.Loop() {
+				_ = try.Try(f)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package workerpool
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/WhiCu/async/group"
+)
+
+var (
+	ErrStopped = errors.New("workerpool: pool stopped")
+	ErrDropped = errors.New("workerpool: task dropped")
+)
+
+// QueueFullError is returned by Submit when the queue is full
+// and the pool uses the Reject policy.
+// It matches group.ErrLimitExceeded with errors.Is.
+type QueueFullError struct {
+	Size int
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("workerpool: queue full (%d tasks)", e.Size)
+}
+
+func (e *QueueFullError) Unwrap() error {
+	return group.ErrLimitExceeded
+}
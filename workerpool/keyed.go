@@ -0,0 +1,114 @@
+package workerpool
+
+import (
+	"hash/maphash"
+	"runtime"
+	"sync"
+)
+
+// Keyed runs tasks on a fixed set of workers and sends all tasks with
+// the same key to the same worker. Tasks sharing a key run one at a time
+// in submission order, while tasks with different keys run in parallel.
+// Like Pool, it converts panics to errors and reports the first one from Wait.
+type Keyed[K comparable] struct {
+	seed   maphash.Seed
+	shards []*Pool
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewKeyed creates a Keyed pool with the given number of workers.
+// Each worker has its own queue configured by opts.
+// If workers is less than 1, runtime.GOMAXPROCS(0) workers are started.
+func NewKeyed[K comparable](workers int, opts ...Option) *Keyed[K] {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	k := &Keyed[K]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*Pool, workers),
+	}
+	for i := range k.shards {
+		k.shards[i] = New(1, opts...)
+		k.shards[i].onError = k.fail
+	}
+	return k
+}
+
+// fail records err if it is the first error of any shard.
+func (k *Keyed[K]) fail(err error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.err == nil {
+		k.err = err
+	}
+}
+
+// firstErr returns the first error recorded by fail.
+func (k *Keyed[K]) firstErr() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.err
+}
+
+func (k *Keyed[K]) shard(key K) *Pool {
+	h := maphash.Comparable(k.seed, key)
+	return k.shards[h%uint64(len(k.shards))]
+}
+
+// Submit queues f on the worker owning key like Pool.Submit.
+func (k *Keyed[K]) Submit(key K, f func()) error {
+	return k.shard(key).Submit(f)
+}
+
+// TrySubmit queues f on the worker owning key like Pool.TrySubmit.
+func (k *Keyed[K]) TrySubmit(key K, f func()) error {
+	return k.shard(key).TrySubmit(f)
+}
+
+// SubmitWait queues f on the worker owning key and waits for it to finish.
+// It returns the panic of f as an error, if any.
+func (k *Keyed[K]) SubmitWait(key K, f func()) error {
+	return k.shard(key).SubmitWait(f)
+}
+
+// Go is like Submit but silently drops f if the pool has been stopped.
+func (k *Keyed[K]) Go(key K, f func()) {
+	_ = k.Submit(key, f)
+}
+
+// Wait blocks until all submitted tasks have finished
+// and returns the first error produced by any of them.
+func (k *Keyed[K]) Wait() error {
+	for _, p := range k.shards {
+		_ = p.Wait()
+	}
+	return k.firstErr()
+}
+
+// StopAndWait stops accepting tasks, waits for all queued and running tasks
+// to finish and shuts down the workers.
+func (k *Keyed[K]) StopAndWait() error {
+	for _, p := range k.shards {
+		p.close()
+	}
+
+	for _, p := range k.shards {
+		_ = p.StopAndWait()
+	}
+	return k.firstErr()
+}
+
+// Stop stops accepting tasks, drops the queued ones and shuts down the workers
+// once the running tasks finish.
+func (k *Keyed[K]) Stop() {
+	for _, p := range k.shards {
+		p.close()
+	}
+	for _, p := range k.shards {
+		p.Stop()
+	}
+}
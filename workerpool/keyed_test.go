@@ -0,0 +1,100 @@
+package workerpool_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/WhiCu/async/internal/testutil"
+	"github.com/WhiCu/async/try"
+	"github.com/WhiCu/async/workerpool"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestKeyed(t *testing.T) {
+	Convey("Given a Keyed pool with 4 workers", t, func() {
+		k := workerpool.NewKeyed[string](4)
+		defer k.Stop()
+
+		Convey("It should run tasks with the same key in order and one at a time", func() {
+			keys := []string{"a", "b", "c", "d", "e"}
+
+			var mu sync.Mutex
+			got := make(map[string][]int)
+			running := make(map[string]*atomic.Int32)
+			for _, key := range keys {
+				running[key] = &atomic.Int32{}
+			}
+
+			var overlap atomic.Bool
+			for i := 0; i < 50; i++ {
+				for _, key := range keys {
+					So(k.Submit(key, func() {
+						if running[key].Add(1) > 1 {
+							overlap.Store(true)
+						}
+						mu.Lock()
+						got[key] = append(got[key], i)
+						mu.Unlock()
+						running[key].Add(-1)
+					}), ShouldBeNil)
+				}
+			}
+
+			So(k.Wait(), ShouldBeNil)
+			So(overlap.Load(), ShouldBeFalse)
+			for _, key := range keys {
+				So(got[key], ShouldHaveLength, 50)
+				for i, v := range got[key] {
+					So(v, ShouldEqual, i)
+				}
+			}
+		})
+
+		Convey("It should run tasks with different keys in parallel", func() {
+			k := workerpool.NewKeyed[int](64)
+			defer k.Stop()
+
+			var running testutil.Gauge
+			for key := 0; key < 8; key++ {
+				k.Go(key, func() { running.Hold(1, 20*time.Millisecond) })
+			}
+
+			So(k.Wait(), ShouldBeNil)
+			So(running.Peak(), ShouldBeGreaterThan, 1)
+		})
+
+		Convey("It should recover panics and report them from Wait()", func() {
+			k.Go("a", func() { panic("boom") })
+
+			var ran atomic.Bool
+			So(k.SubmitWait("a", func() { ran.Store(true) }), ShouldBeNil)
+			So(ran.Load(), ShouldBeTrue)
+
+			err := k.Wait()
+			So(err, ShouldNotBeNil)
+			So(try.AsPanicError(err), ShouldBeTrue)
+		})
+
+		Convey("It should report the first error across all workers", func() {
+			k := workerpool.NewKeyed[int](16)
+			defer k.Stop()
+
+			for key := 0; key < 16; key++ {
+				k.Go(key, func() { panic(key) })
+				So(k.SubmitWait(key, func() {}), ShouldBeNil)
+			}
+
+			var pe *try.PanicError
+			So(errors.As(k.Wait(), &pe), ShouldBeTrue)
+			So(pe.Value, ShouldEqual, 0)
+		})
+
+		Convey("It should return ErrStopped after StopAndWait()", func() {
+			So(k.StopAndWait(), ShouldBeNil)
+			So(k.Submit("a", func() {}), ShouldEqual, workerpool.ErrStopped)
+		})
+	})
+}
@@ -0,0 +1,78 @@
+package workerpool
+
+import "time"
+
+const defaultIdleTimeout = time.Second
+
+// Option configures a Pool.
+type Option func(*options)
+
+type options struct {
+	queueSize int
+	policy    Policy
+
+	autoscale   bool
+	minWorkers  int
+	idleTimeout time.Duration
+
+	aging time.Duration
+}
+
+// WithQueueSize sets the number of tasks that may wait in the queue
+// before Submit blocks. A size less than 1 makes the queue unbounded.
+// By default the queue holds as many tasks as there are workers.
+func WithQueueSize(n int) Option {
+	return func(o *options) {
+		o.queueSize = n
+	}
+}
+
+// Policy defines what Submit does when the queue is full.
+type Policy int
+
+const (
+	// Block makes Submit wait until there is room in the queue.
+	Block Policy = iota
+	// DropNewest discards the submitted task.
+	DropNewest
+	// DropOldest discards the task that has been queued the longest
+	// to make room for the submitted one. With priorities, it discards the oldest
+	// of the tasks with the lowest priority, or the submitted task if its
+	// priority is lower than theirs.
+	DropOldest
+	// Reject makes Submit return a *QueueFullError.
+	Reject
+)
+
+// WithPolicy sets the Policy applied when the queue is full. The default is Block.
+// Dropped tasks are never run; SubmitWait and result handles report ErrDropped for them.
+func WithPolicy(policy Policy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// WithAutoscale makes the number of workers elastic.
+// The pool starts with min workers and starts another one, up to the
+// number passed to New, whenever a task is queued while no worker is idle.
+// Workers that stay idle for longer than idleTimeout exit until only min remain.
+// If idleTimeout is not positive, one second is used.
+func WithAutoscale(min int, idleTimeout time.Duration) Option {
+	return func(o *options) {
+		if idleTimeout <= 0 {
+			idleTimeout = defaultIdleTimeout
+		}
+		o.autoscale = true
+		o.minWorkers = max(min, 0)
+		o.idleTimeout = idleTimeout
+	}
+}
+
+// WithAging makes queued tasks gain one priority level for every interval
+// they spend waiting, so low-priority tasks are eventually run
+// even while higher-priority ones keep arriving.
+func WithAging(interval time.Duration) Option {
+	return func(o *options) {
+		o.aging = interval
+	}
+}
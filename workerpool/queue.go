@@ -0,0 +1,112 @@
+package workerpool
+
+import (
+	"container/heap"
+	"time"
+)
+
+// item is a task waiting in the queue together with its ordering keys.
+type item struct {
+	task task
+	// rank is the priority adjusted for aging: a task gains one level
+	// for every aging interval it has spent in the queue.
+	rank float64
+	seq  uint64
+}
+
+// queue is a priority queue of tasks.
+// Tasks with a higher priority are popped first and tasks with equal
+// priority are popped in submission order.
+type queue struct {
+	items []item
+	seq   uint64
+
+	aging time.Duration
+	epoch time.Time
+}
+
+func newQueue(aging time.Duration) *queue {
+	return &queue{
+		aging: aging,
+		epoch: time.Now(),
+	}
+}
+
+func (q *queue) Len() int { return len(q.items) }
+
+func (q *queue) Less(i, j int) bool {
+	a, b := &q.items[i], &q.items[j]
+	if q.aging > 0 {
+		if a.rank != b.rank {
+			return a.rank > b.rank
+		}
+	} else if a.task.priority != b.task.priority {
+		return a.task.priority > b.task.priority
+	}
+	return a.seq < b.seq
+}
+
+func (q *queue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *queue) Push(x any) { q.items = append(q.items, x.(item)) }
+
+func (q *queue) Pop() any {
+	n := len(q.items) - 1
+	it := q.items[n]
+	q.items[n] = item{}
+	q.items = q.items[:n]
+	return it
+}
+
+// push adds t to the queue.
+func (q *queue) push(t task) {
+	it := item{
+		task: t,
+		seq:  q.seq,
+	}
+	q.seq++
+
+	if q.aging > 0 {
+		// Ranks only depend on the enqueue time, so aging never reorders the heap:
+		// priority + (now-enqueued)/aging compares the same as priority - enqueued/aging.
+		enqueued := float64(time.Since(q.epoch)) / float64(q.aging)
+		it.rank = float64(t.priority) - enqueued
+	}
+	heap.Push(q, it)
+}
+
+// pop removes and returns the task that should run next.
+func (q *queue) pop() task {
+	return heap.Pop(q).(item).task
+}
+
+// evict makes room for t by removing the oldest of the queued tasks with
+// the lowest priority and returns it. If t has a lower priority than all of
+// them, nothing is removed and evict returns false, so t should be dropped instead.
+func (q *queue) evict(t task) (task, bool) {
+	if len(q.items) == 0 {
+		return task{}, false
+	}
+
+	victim := 0
+	for i := range q.items {
+		a, v := &q.items[i], &q.items[victim]
+		if a.task.priority < v.task.priority || a.task.priority == v.task.priority && a.seq < v.seq {
+			victim = i
+		}
+	}
+	if t.priority < q.items[victim].task.priority {
+		return task{}, false
+	}
+	return heap.Remove(q, victim).(item).task, true
+}
+
+// drain removes and returns all queued tasks.
+func (q *queue) drain() []task {
+	tasks := make([]task, len(q.items))
+	for i, it := range q.items {
+		tasks[i] = it.task
+	}
+	q.items = nil
+	return tasks
+}
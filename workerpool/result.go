@@ -0,0 +1,225 @@
+package workerpool
+
+import (
+	"iter"
+	"slices"
+	"sync"
+)
+
+// Handle is the pending result of a task submitted to a ResultPool.
+type Handle[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+
+	// seq is the submission number of the task; yielded is set once Results yielded it.
+	seq     int
+	yielded bool
+}
+
+// Done returns a channel that is closed when the task has finished.
+func (h *Handle[T]) Done() <-chan struct{} {
+	return h.done
+}
+
+// Value blocks until the task has finished and returns its result.
+// A panic in the task is returned as a *try.PanicError.
+func (h *Handle[T]) Value() (T, error) {
+	<-h.done
+	return h.value, h.err
+}
+
+// ResultPool is a Pool whose tasks return a value.
+// It keeps the result of every submitted task until it is yielded by Results,
+// so a long-lived ResultPool only holds the results that were not collected yet.
+type ResultPool[T any] struct {
+	pool    *Pool
+	ordered bool
+
+	mu      sync.Mutex
+	changed *sync.Cond
+	// submitted is the number of tasks submitted so far.
+	submitted int
+	// pending holds the handles not yet yielded, in submission order;
+	// its first handle is never yielded.
+	pending []*Handle[T]
+	// ready holds the finished handles not yet yielded, in completion order.
+	// It is only used in CompletionOrder.
+	ready []*Handle[T]
+}
+
+// Order is the order in which ResultPool.Results yields results.
+type Order int
+
+const (
+	// CompletionOrder yields results as the tasks finish.
+	CompletionOrder Order = iota
+	// SubmissionOrder yields results in the order the tasks were submitted.
+	SubmissionOrder
+)
+
+// NewResultPool creates a ResultPool backed by a Pool with the given workers and options,
+// whose Results are yielded in the given order.
+func NewResultPool[T any](workers int, order Order, opts ...Option) *ResultPool[T] {
+	p := &ResultPool[T]{
+		pool:    New(workers, opts...),
+		ordered: order == SubmissionOrder,
+	}
+	p.changed = sync.NewCond(&p.mu)
+	return p
+}
+
+func (p *ResultPool[T]) submit(f func() (T, error), block bool) (*Handle[T], error) {
+	h := &Handle[T]{done: make(chan struct{})}
+
+	p.mu.Lock()
+	h.seq = p.submitted
+	p.submitted++
+	p.pending = append(p.pending, h)
+	p.mu.Unlock()
+
+	t := task{
+		f: func() error {
+			v, err := f()
+			h.value = v
+			return err
+		},
+		done: func(err error) {
+			p.complete(h, err)
+		},
+	}
+	if err := p.pool.submit(t, block); err != nil {
+		p.complete(h, err)
+		return h, err
+	}
+	return h, nil
+}
+
+func (p *ResultPool[T]) complete(h *Handle[T], err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h.err = err
+	close(h.done)
+
+	if !p.ordered {
+		p.ready = append(p.ready, h)
+		p.changed.Broadcast()
+	}
+}
+
+// take marks h as yielded and drops the yielded handles from the front of pending.
+// It must be called with p.mu held.
+func (p *ResultPool[T]) take(h *Handle[T]) {
+	h.yielded = true
+
+	n := 0
+	for n < len(p.pending) && p.pending[n].yielded {
+		p.pending[n] = nil
+		n++
+	}
+	p.pending = p.pending[n:]
+}
+
+// Submit queues f for execution like Pool.Submit.
+// If the task is rejected, dropped or the pool has been stopped,
+// the returned handle holds the corresponding error.
+func (p *ResultPool[T]) Submit(f func() (T, error)) *Handle[T] {
+	h, _ := p.submit(f, true)
+	return h
+}
+
+// TrySubmit is like Submit but never blocks.
+// It returns the same errors as Pool.TrySubmit; the handle then holds the same error.
+func (p *ResultPool[T]) TrySubmit(f func() (T, error)) (*Handle[T], error) {
+	return p.submit(f, false)
+}
+
+// Results returns an iterator over the results of the tasks submitted
+// before it is called, in the Order passed to NewResultPool.
+// Iteration blocks until the next result is ready.
+// Each result is yielded only once, even across calls to Results,
+// and is no longer held by the pool once yielded.
+func (p *ResultPool[T]) Results() iter.Seq2[T, error] {
+	p.mu.Lock()
+	n := p.submitted
+	p.mu.Unlock()
+
+	if p.ordered {
+		return p.inSubmissionOrder(n)
+	}
+	return p.inCompletionOrder(n)
+}
+
+func (p *ResultPool[T]) inSubmissionOrder(n int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			p.mu.Lock()
+			if len(p.pending) == 0 || p.pending[0].seq >= n {
+				p.mu.Unlock()
+				return
+			}
+			h := p.pending[0]
+			p.take(h)
+			p.mu.Unlock()
+
+			if !yield(h.Value()) {
+				return
+			}
+		}
+	}
+}
+
+func (p *ResultPool[T]) inCompletionOrder(n int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			h := p.nextReady(n)
+			if h == nil {
+				return
+			}
+			if !yield(h.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// nextReady waits for the next finished task submitted before the n-th one,
+// takes it and returns it, or returns nil if all of them have been yielded.
+func (p *ResultPool[T]) nextReady(n int) *Handle[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for {
+		for i, h := range p.ready {
+			if h.seq < n {
+				p.ready = slices.Delete(p.ready, i, i+1)
+				p.take(h)
+				return h
+			}
+		}
+		// The first pending handle is not yielded; since it is not ready either,
+		// it is still running.
+		if len(p.pending) == 0 || p.pending[0].seq >= n {
+			return nil
+		}
+		p.changed.Wait()
+	}
+}
+
+// Wait blocks until all submitted tasks have finished
+// and returns the first error produced by any of them.
+func (p *ResultPool[T]) Wait() error {
+	return p.pool.Wait()
+}
+
+// StopAndWait stops accepting tasks and waits for the queued and running ones to finish.
+func (p *ResultPool[T]) StopAndWait() error {
+	return p.pool.StopAndWait()
+}
+
+// Stop stops accepting tasks and drops the queued ones;
+// their handles hold ErrStopped.
+func (p *ResultPool[T]) Stop() {
+	p.pool.Stop()
+}
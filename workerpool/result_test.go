@@ -0,0 +1,142 @@
+package workerpool_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/WhiCu/async/try"
+	"github.com/WhiCu/async/workerpool"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func sleepThen(d time.Duration, v int) func() (int, error) {
+	return func() (int, error) {
+		time.Sleep(d)
+		return v, nil
+	}
+}
+
+func TestResultPool(t *testing.T) {
+	Convey("Given a ResultPool", t, func() {
+		p := workerpool.NewResultPool[int](4, workerpool.CompletionOrder)
+		defer p.Stop()
+
+		Convey("It should return the value through the handle", func() {
+			h := p.Submit(func() (int, error) { return 42, nil })
+
+			v, err := h.Value()
+			So(v, ShouldEqual, 42)
+			So(err, ShouldBeNil)
+
+			select {
+			case <-h.Done():
+			default:
+				So("handle should be done", ShouldBeEmpty)
+			}
+		})
+
+		Convey("It should yield results in completion order", func() {
+			p.Submit(sleepThen(60*time.Millisecond, 3))
+			p.Submit(sleepThen(40*time.Millisecond, 2))
+			p.Submit(sleepThen(20*time.Millisecond, 1))
+
+			var got []int
+			for v, err := range p.Results() {
+				So(err, ShouldBeNil)
+				got = append(got, v)
+			}
+			So(got, ShouldResemble, []int{1, 2, 3})
+		})
+
+		Convey("It should report errors and panics", func() {
+			testErr := errors.New("fail")
+			p.Submit(func() (int, error) { return 0, testErr })
+			p.Submit(func() (int, error) { panic("boom") })
+
+			var errs []error
+			for _, err := range p.Results() {
+				errs = append(errs, err)
+			}
+			So(errs, ShouldHaveLength, 2)
+
+			var failed, panicked int
+			for _, err := range errs {
+				switch {
+				case errors.Is(err, testErr):
+					failed++
+				case try.AsPanicError(err):
+					panicked++
+				}
+			}
+			So(failed, ShouldEqual, 1)
+			So(panicked, ShouldEqual, 1)
+			So(p.Wait(), ShouldNotBeNil)
+		})
+
+		Convey("It should yield each result only once", func() {
+			for i := 0; i < 3; i++ {
+				p.Submit(sleepThen(0, i))
+			}
+
+			var n int
+			for range p.Results() {
+				n++
+			}
+			So(n, ShouldEqual, 3)
+
+			p.Submit(sleepThen(0, 3))
+			var got []int
+			for v := range p.Results() {
+				got = append(got, v)
+			}
+			So(got, ShouldResemble, []int{3})
+		})
+
+		Convey("It should hold ErrStopped in handles after StopAndWait()", func() {
+			So(p.StopAndWait(), ShouldBeNil)
+
+			_, err := p.Submit(func() (int, error) { return 1, nil }).Value()
+			So(err, ShouldEqual, workerpool.ErrStopped)
+		})
+	})
+
+	Convey("Given a ResultPool with ordered results", t, func() {
+		p := workerpool.NewResultPool[int](4, workerpool.SubmissionOrder)
+		defer p.Stop()
+
+		Convey("It should yield results in submission order", func() {
+			p.Submit(sleepThen(60*time.Millisecond, 1))
+			p.Submit(sleepThen(40*time.Millisecond, 2))
+			p.Submit(sleepThen(20*time.Millisecond, 3))
+
+			var got []int
+			for v, err := range p.Results() {
+				So(err, ShouldBeNil)
+				got = append(got, v)
+			}
+			So(got, ShouldResemble, []int{1, 2, 3})
+		})
+
+		Convey("It should stop when the loop breaks", func() {
+			for i := 0; i < 5; i++ {
+				p.Submit(sleepThen(0, i))
+			}
+
+			var got []int
+			for v := range p.Results() {
+				got = append(got, v)
+				if len(got) == 2 {
+					break
+				}
+			}
+			So(got, ShouldResemble, []int{0, 1})
+
+			got = nil
+			for v := range p.Results() {
+				got = append(got, v)
+			}
+			So(got, ShouldResemble, []int{2, 3, 4})
+		})
+	})
+}
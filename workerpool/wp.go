@@ -1 +1,434 @@
+// Package workerpool provides a pool of long-lived workers that execute
+// tasks from a shared queue.
+// Unlike the group packages, which start a goroutine per task,
+// a Pool runs every task on one of a bounded number of goroutines.
 package workerpool
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/WhiCu/async/group"
+	"github.com/WhiCu/async/try"
+)
+
+var (
+	_ group.Runner = (*Pool)(nil)
+	_ group.Waiter = (*Pool)(nil)
+)
+
+// task is a unit of work waiting in the queue.
+// If done is set, it is called with the outcome of the task exactly once.
+type task struct {
+	f        func() error
+	done     func(error)
+	priority int
+}
+
+func (t task) finish(err error) {
+	if t.done != nil {
+		t.done(err)
+	}
+}
+
+// Pool executes submitted tasks on a bounded number of workers.
+// Panics in tasks are converted to errors with try, so a failing task
+// never kills a worker; the first error is reported by Wait.
+type Pool struct {
+	mu sync.Mutex
+
+	queue     *queue
+	queueSize int
+	notFull   *sync.Cond
+	drained   *sync.Cond
+
+	// wake is signalled when a task is queued while some workers are idle.
+	wake     chan struct{}
+	quit     chan struct{}
+	quitOnce sync.Once
+
+	workers     sync.WaitGroup
+	minWorkers  int
+	maxWorkers  int
+	idleTimeout time.Duration
+	running     int
+	peak        int
+	idle        int
+
+	// active is the number of queued and running tasks.
+	active int
+	closed bool
+
+	policy    Policy
+	overflows uint64
+
+	err error
+	// onError, if set, is called with the error of every failed task.
+	onError func(error)
+}
+
+// New creates a Pool and starts its workers.
+// If workers is less than 1, runtime.GOMAXPROCS(0) workers are started.
+// With WithAutoscale, workers is the maximum number of workers.
+func New(workers int, opts ...Option) *Pool {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	o := options{queueSize: workers, minWorkers: workers}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	p := &Pool{
+		queue:      newQueue(o.aging),
+		queueSize:  o.queueSize,
+		policy:     o.policy,
+		minWorkers: min(o.minWorkers, workers),
+		maxWorkers: workers,
+		wake:       make(chan struct{}, 1),
+		quit:       make(chan struct{}),
+	}
+	if o.autoscale {
+		p.idleTimeout = o.idleTimeout
+	}
+	p.notFull = sync.NewCond(&p.mu)
+	p.drained = sync.NewCond(&p.mu)
+
+	p.mu.Lock()
+	for range p.minWorkers {
+		p.spawn()
+	}
+	p.mu.Unlock()
+	return p
+}
+
+// spawn starts a new worker. It must be called with p.mu held.
+func (p *Pool) spawn() {
+	p.running++
+	p.peak = max(p.peak, p.running)
+	p.workers.Add(1)
+	go p.worker()
+}
+
+func (p *Pool) worker() {
+	exited := false
+	defer func() {
+		if !exited {
+			p.replace()
+		}
+		p.workers.Done()
+	}()
+
+	var timer *time.Timer
+	if p.idleTimeout > 0 {
+		timer = time.NewTimer(p.idleTimeout)
+		defer timer.Stop()
+	}
+
+	for {
+		t, ok := p.next(timer)
+		if !ok {
+			exited = true
+			return
+		}
+		p.run(t)
+	}
+}
+
+// replace accounts for a worker killed by a task calling runtime.Goexit
+// and starts another one if there is work left for it.
+func (p *Pool) replace() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running--
+	select {
+	case <-p.quit:
+	default:
+		if p.running < p.minWorkers || p.queue.Len() > 0 {
+			p.spawn()
+		}
+	}
+}
+
+// next blocks until a task is available. It returns false if the pool
+// is stopped or the worker has been idle for too long and should exit.
+func (p *Pool) next(timer *time.Timer) (task, bool) {
+	p.mu.Lock()
+	for p.queue.Len() == 0 {
+		p.idle++
+		p.mu.Unlock()
+
+		var timeout <-chan time.Time
+		if timer != nil {
+			timer.Reset(p.idleTimeout)
+			timeout = timer.C
+		}
+
+		select {
+		case <-p.wake:
+		case <-timeout:
+			p.mu.Lock()
+			p.idle--
+			if p.queue.Len() == 0 && p.running > p.minWorkers {
+				p.running--
+				p.mu.Unlock()
+				return task{}, false
+			}
+			continue
+		case <-p.quit:
+			p.mu.Lock()
+			p.idle--
+			p.running--
+			p.mu.Unlock()
+			return task{}, false
+		}
+
+		p.mu.Lock()
+		p.idle--
+	}
+
+	t := p.queue.pop()
+
+	// Pass the wake-up on if there is more work for other idle workers.
+	if p.queue.Len() > 0 && p.idle > 0 {
+		p.signal()
+	}
+	p.notFull.Signal()
+	p.mu.Unlock()
+	return t, true
+}
+
+// run executes t. If t calls runtime.Goexit, it is finished with try.ErrGoexit
+// and the worker exits.
+func (p *Pool) run(t task) {
+	try.Run(t.f, func(err error) {
+		t.finish(err)
+		if err != nil && p.onError != nil {
+			p.onError(err)
+		}
+
+		p.mu.Lock()
+		if err != nil && p.err == nil {
+			p.err = err
+		}
+		p.active--
+		if p.active == 0 {
+			p.drained.Broadcast()
+		}
+		p.mu.Unlock()
+	})
+}
+
+// signal wakes one idle worker. It must be called with p.mu held.
+func (p *Pool) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Pool) full() bool {
+	return p.queueSize > 0 && p.queue.Len() >= p.queueSize
+}
+
+// submit queues t, applying the overflow policy if the queue is full.
+// If it returns nil, t is guaranteed to be finished, either by a worker
+// or with ErrDropped or ErrStopped.
+func (p *Pool) submit(t task, block bool) error {
+	p.mu.Lock()
+
+	if !p.closed && p.full() {
+		switch p.policy {
+		case DropNewest:
+			p.overflows++
+			p.mu.Unlock()
+			t.finish(ErrDropped)
+			return nil
+		case DropOldest:
+			p.overflows++
+			evicted, ok := p.queue.evict(t)
+			if !ok {
+				p.mu.Unlock()
+				t.finish(ErrDropped)
+				return nil
+			}
+			p.active--
+			defer evicted.finish(ErrDropped)
+		case Reject:
+			p.overflows++
+			p.mu.Unlock()
+			return &QueueFullError{Size: p.queueSize}
+		default:
+			if !block {
+				p.overflows++
+				p.mu.Unlock()
+				return group.ErrLimitExceeded
+			}
+			for !p.closed && p.full() {
+				p.notFull.Wait()
+			}
+		}
+	}
+	if p.closed {
+		p.mu.Unlock()
+		return ErrStopped
+	}
+
+	p.queue.push(t)
+	p.active++
+	switch {
+	case p.idle > 0:
+		p.signal()
+	case p.running < p.maxWorkers:
+		p.spawn()
+	}
+	p.mu.Unlock()
+	return nil
+}
+
+func wrap(f func()) func() error {
+	return func() error {
+		f()
+		return nil
+	}
+}
+
+// Submit queues f for execution. If the queue is full, it blocks or
+// drops or rejects a task according to the pool's Policy.
+// It returns ErrStopped if the pool has been stopped.
+func (p *Pool) Submit(f func()) error {
+	return p.submit(task{f: wrap(f)}, true)
+}
+
+// TrySubmit is like Submit but never blocks.
+// With the Block policy it returns group.ErrLimitExceeded if the queue is full.
+func (p *Pool) TrySubmit(f func()) error {
+	return p.submit(task{f: wrap(f)}, false)
+}
+
+// SubmitPriority is like Submit but queues f with the given priority.
+// Queued tasks with a higher priority run first; Submit uses priority 0.
+// With WithAging, waiting tasks gradually gain priority so they are not starved.
+func (p *Pool) SubmitPriority(priority int, f func()) error {
+	return p.submit(task{f: wrap(f), priority: priority}, true)
+}
+
+// TrySubmitPriority is like SubmitPriority but does not block.
+func (p *Pool) TrySubmitPriority(priority int, f func()) error {
+	return p.submit(task{f: wrap(f), priority: priority}, false)
+}
+
+// SubmitWait queues f and waits for it to finish.
+// It returns the panic of f as an error, if any, or ErrDropped or
+// ErrStopped if the task was not run.
+func (p *Pool) SubmitWait(f func()) error {
+	done := make(chan error, 1)
+	t := task{
+		f:    wrap(f),
+		done: func(err error) { done <- err },
+	}
+	if err := p.submit(t, true); err != nil {
+		return err
+	}
+	return <-done
+}
+
+// Go is like Submit but silently drops f if the pool has been stopped.
+func (p *Pool) Go(f func()) {
+	_ = p.Submit(f)
+}
+
+// TryGo is an alias for TrySubmit.
+func (p *Pool) TryGo(f func()) error {
+	return p.TrySubmit(f)
+}
+
+// Wait blocks until all submitted tasks have finished
+// and returns the first error produced by any of them.
+// The pool keeps accepting tasks after Wait returns.
+func (p *Pool) Wait() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for p.active > 0 {
+		p.drained.Wait()
+	}
+	return p.err
+}
+
+// Workers returns the number of running workers.
+func (p *Pool) Workers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.running
+}
+
+// PeakWorkers returns the largest number of workers that ran at the same time.
+func (p *Pool) PeakWorkers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.peak
+}
+
+// IdleWorkers returns the number of workers waiting for a task.
+func (p *Pool) IdleWorkers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.idle
+}
+
+// Overflows returns how many tasks were dropped or rejected because the queue was full.
+// Submissions that waited for room under the Block policy are not counted.
+func (p *Pool) Overflows() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.overflows
+}
+
+// close stops the pool from accepting new tasks.
+func (p *Pool) close() {
+	p.mu.Lock()
+	p.closed = true
+	p.notFull.Broadcast()
+	p.mu.Unlock()
+}
+
+// shutdown tells the workers to exit and waits for them.
+func (p *Pool) shutdown() {
+	p.quitOnce.Do(func() {
+		close(p.quit)
+	})
+	p.workers.Wait()
+}
+
+// StopAndWait stops accepting tasks, waits for all queued and running tasks
+// to finish and shuts down the workers.
+// It returns the first error produced by any task.
+func (p *Pool) StopAndWait() error {
+	p.close()
+	err := p.Wait()
+	p.shutdown()
+	return err
+}
+
+// Stop stops accepting tasks, drops the queued ones and shuts down the workers
+// once the running tasks finish. Dropped tasks are never run.
+func (p *Pool) Stop() {
+	p.close()
+
+	p.mu.Lock()
+	dropped := p.queue.drain()
+	p.active -= len(dropped)
+	if p.active == 0 {
+		p.drained.Broadcast()
+	}
+	p.mu.Unlock()
+
+	for _, t := range dropped {
+		t.finish(ErrStopped)
+	}
+	p.shutdown()
+}
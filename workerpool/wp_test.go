@@ -0,0 +1,345 @@
+package workerpool_test
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/WhiCu/async/group"
+	"github.com/WhiCu/async/internal/testutil"
+	"github.com/WhiCu/async/try"
+	"github.com/WhiCu/async/workerpool"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPool(t *testing.T) {
+	Convey("Given a pool with 4 workers", t, func() {
+		p := workerpool.New(4)
+		defer p.Stop()
+
+		Convey("It should run all submitted tasks", func() {
+			var count atomic.Int32
+			for i := 0; i < 100; i++ {
+				So(p.Submit(func() { count.Add(1) }), ShouldBeNil)
+			}
+
+			So(p.Wait(), ShouldBeNil)
+			So(count.Load(), ShouldEqual, 100)
+		})
+
+		Convey("It should never run more tasks than workers", func() {
+			var running testutil.Gauge
+			for i := 0; i < 20; i++ {
+				p.Go(func() { running.Hold(1, 5*time.Millisecond) })
+			}
+
+			So(p.Wait(), ShouldBeNil)
+			So(running.Peak(), ShouldBeLessThanOrEqualTo, 4)
+		})
+
+		Convey("It should recover panics and keep working", func() {
+			p.Go(func() { panic("boom") })
+
+			err := p.Wait()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "boom")
+			So(try.AsPanicError(err), ShouldBeTrue)
+
+			var ran atomic.Bool
+			So(p.SubmitWait(func() { ran.Store(true) }), ShouldBeNil)
+			So(ran.Load(), ShouldBeTrue)
+		})
+
+		Convey("It should survive tasks calling runtime.Goexit", func() {
+			for i := 0; i < 8; i++ {
+				So(p.SubmitWait(func() { runtime.Goexit() }), ShouldEqual, try.ErrGoexit)
+			}
+			So(p.Wait(), ShouldEqual, try.ErrGoexit)
+
+			var count atomic.Int32
+			for i := 0; i < 8; i++ {
+				p.Go(func() { count.Add(1) })
+			}
+			So(p.Wait(), ShouldEqual, try.ErrGoexit)
+			So(count.Load(), ShouldEqual, 8)
+			So(waitFor(func() bool { return p.Workers() == 4 }), ShouldBeTrue)
+		})
+
+		Convey("It should return the panic of a task from SubmitWait()", func() {
+			err := p.SubmitWait(func() { panic("fail") })
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "fail")
+		})
+
+		Convey("It should return ErrStopped after StopAndWait()", func() {
+			var count atomic.Int32
+			for i := 0; i < 10; i++ {
+				p.Go(func() {
+					time.Sleep(time.Millisecond)
+					count.Add(1)
+				})
+			}
+
+			So(p.StopAndWait(), ShouldBeNil)
+			So(count.Load(), ShouldEqual, 10)
+			So(p.Submit(func() {}), ShouldEqual, workerpool.ErrStopped)
+			So(p.TrySubmit(func() {}), ShouldEqual, workerpool.ErrStopped)
+		})
+	})
+
+	Convey("Given a pool with one worker and a queue of one", t, func() {
+		p := workerpool.New(1, workerpool.WithQueueSize(1))
+		release := make(chan struct{})
+
+		So(p.Submit(func() { <-release }), ShouldBeNil)
+		So(p.Submit(func() {}), ShouldBeNil)
+
+		Convey("TrySubmit() should return ErrLimitExceeded", func() {
+			So(p.TrySubmit(func() {}), ShouldEqual, group.ErrLimitExceeded)
+
+			close(release)
+			So(p.StopAndWait(), ShouldBeNil)
+		})
+
+		Convey("Stop() should drop queued tasks", func() {
+			var ran atomic.Bool
+			done := make(chan error, 1)
+			go func() {
+				done <- p.SubmitWait(func() { ran.Store(true) })
+			}()
+
+			time.Sleep(10 * time.Millisecond)
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				close(release)
+			}()
+			p.Stop()
+
+			So(errors.Is(<-done, workerpool.ErrStopped), ShouldBeTrue)
+			So(ran.Load(), ShouldBeFalse)
+		})
+	})
+}
+
+func TestPool_Autoscale(t *testing.T) {
+	Convey("Given an autoscaling pool with 1 to 4 workers", t, func() {
+		p := workerpool.New(4, workerpool.WithAutoscale(1, 20*time.Millisecond))
+		defer p.Stop()
+
+		So(p.Workers(), ShouldEqual, 1)
+
+		Convey("It should grow while the queue backs up", func() {
+			release := make(chan struct{})
+			for i := 0; i < 8; i++ {
+				p.Go(func() { <-release })
+			}
+
+			So(p.Workers(), ShouldEqual, 4)
+			So(p.PeakWorkers(), ShouldEqual, 4)
+
+			close(release)
+			So(p.Wait(), ShouldBeNil)
+
+			Convey("And shrink back to the minimum once idle", func() {
+				So(waitFor(func() bool { return p.Workers() == 1 }), ShouldBeTrue)
+				So(p.IdleWorkers(), ShouldEqual, 1)
+				So(p.PeakWorkers(), ShouldEqual, 4)
+			})
+		})
+
+		Convey("It should not grow while a worker is idle", func() {
+			So(waitFor(func() bool { return p.IdleWorkers() == 1 }), ShouldBeTrue)
+			So(p.SubmitWait(func() {}), ShouldBeNil)
+			So(p.PeakWorkers(), ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given an autoscaling pool that can shrink to zero", t, func() {
+		p := workerpool.New(2, workerpool.WithAutoscale(0, 10*time.Millisecond))
+		defer p.Stop()
+
+		So(p.Workers(), ShouldEqual, 0)
+
+		Convey("It should start a worker on demand", func() {
+			var ran atomic.Bool
+			So(p.SubmitWait(func() { ran.Store(true) }), ShouldBeNil)
+			So(ran.Load(), ShouldBeTrue)
+
+			So(waitFor(func() bool { return p.Workers() == 0 }), ShouldBeTrue)
+			So(p.SubmitWait(func() {}), ShouldBeNil)
+		})
+	})
+}
+
+func TestPool_Priority(t *testing.T) {
+	Convey("Given a saturated pool with one worker", t, func() {
+		sat := newSaturation()
+
+		Convey("It should run queued tasks by priority", func() {
+			p := workerpool.New(1, workerpool.WithQueueSize(0))
+			So(p.Submit(sat.block), ShouldBeNil)
+			<-sat.started
+
+			So(p.Submit(sat.record(0)), ShouldBeNil)
+			So(p.SubmitPriority(1, sat.record(1)), ShouldBeNil)
+			So(p.SubmitPriority(5, sat.record(5)), ShouldBeNil)
+			So(p.SubmitPriority(-1, sat.record(-1)), ShouldBeNil)
+			So(p.SubmitPriority(3, sat.record(3)), ShouldBeNil)
+			So(p.SubmitPriority(5, sat.record(6)), ShouldBeNil)
+
+			close(sat.release)
+			So(p.StopAndWait(), ShouldBeNil)
+			So(sat.order, ShouldResemble, []int{5, 6, 3, 1, 0, -1})
+		})
+
+		Convey("It should let long-waiting tasks overtake with aging", func() {
+			p := workerpool.New(1, workerpool.WithQueueSize(0), workerpool.WithAging(time.Millisecond))
+			So(p.Submit(sat.block), ShouldBeNil)
+			<-sat.started
+
+			So(p.SubmitPriority(0, sat.record(0)), ShouldBeNil)
+			time.Sleep(50 * time.Millisecond)
+			So(p.SubmitPriority(10, sat.record(10)), ShouldBeNil)
+			So(p.SubmitPriority(1000, sat.record(1000)), ShouldBeNil)
+
+			close(sat.release)
+			So(p.StopAndWait(), ShouldBeNil)
+			So(sat.order, ShouldResemble, []int{1000, 0, 10})
+		})
+	})
+}
+
+func TestPool_Policy(t *testing.T) {
+	Convey("Given a saturated pool with one worker and a queue of two", t, func() {
+		sat := newSaturation()
+
+		saturate := func(policy workerpool.Policy) *workerpool.Pool {
+			p := workerpool.New(1, workerpool.WithQueueSize(2), workerpool.WithPolicy(policy))
+			So(p.Submit(sat.block), ShouldBeNil)
+			<-sat.started
+			return p
+		}
+
+		Convey("Block should wait for room in the queue", func() {
+			p := saturate(workerpool.Block)
+			So(p.Submit(sat.record(1)), ShouldBeNil)
+			So(p.Submit(sat.record(2)), ShouldBeNil)
+
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				close(sat.release)
+			}()
+			So(p.Submit(sat.record(3)), ShouldBeNil)
+
+			So(p.StopAndWait(), ShouldBeNil)
+			So(sat.order, ShouldResemble, []int{1, 2, 3})
+			So(p.Overflows(), ShouldEqual, 0)
+		})
+
+		Convey("DropNewest should discard the submitted task", func() {
+			p := saturate(workerpool.DropNewest)
+			So(p.Submit(sat.record(1)), ShouldBeNil)
+			So(p.Submit(sat.record(2)), ShouldBeNil)
+			So(p.Submit(sat.record(3)), ShouldBeNil)
+			So(p.SubmitWait(sat.record(4)), ShouldEqual, workerpool.ErrDropped)
+
+			close(sat.release)
+			So(p.StopAndWait(), ShouldBeNil)
+			So(sat.order, ShouldResemble, []int{1, 2})
+			So(p.Overflows(), ShouldEqual, 2)
+		})
+
+		Convey("DropOldest should discard the longest queued task", func() {
+			p := saturate(workerpool.DropOldest)
+			dropped := make(chan error, 1)
+			go func() {
+				dropped <- p.SubmitWait(sat.record(1))
+			}()
+			time.Sleep(10 * time.Millisecond)
+
+			So(p.Submit(sat.record(2)), ShouldBeNil)
+			So(p.Submit(sat.record(3)), ShouldBeNil)
+			So(<-dropped, ShouldEqual, workerpool.ErrDropped)
+
+			close(sat.release)
+			So(p.StopAndWait(), ShouldBeNil)
+			So(sat.order, ShouldResemble, []int{2, 3})
+			So(p.Overflows(), ShouldEqual, 1)
+		})
+
+		Convey("DropOldest should discard the lowest priority first", func() {
+			p := saturate(workerpool.DropOldest)
+			So(p.SubmitPriority(5, sat.record(5)), ShouldBeNil)
+			So(p.SubmitPriority(0, sat.record(0)), ShouldBeNil)
+			So(p.SubmitPriority(1, sat.record(1)), ShouldBeNil)
+			So(p.SubmitPriority(-1, sat.record(-1)), ShouldBeNil)
+
+			close(sat.release)
+			So(p.StopAndWait(), ShouldBeNil)
+			So(sat.order, ShouldResemble, []int{5, 1})
+			So(p.Overflows(), ShouldEqual, 2)
+		})
+
+		Convey("Reject should return a QueueFullError", func() {
+			p := saturate(workerpool.Reject)
+			So(p.Submit(sat.record(1)), ShouldBeNil)
+			So(p.Submit(sat.record(2)), ShouldBeNil)
+
+			err := p.Submit(sat.record(3))
+			var full *workerpool.QueueFullError
+			So(errors.As(err, &full), ShouldBeTrue)
+			So(full.Size, ShouldEqual, 2)
+			So(errors.Is(err, group.ErrLimitExceeded), ShouldBeTrue)
+
+			close(sat.release)
+			So(p.StopAndWait(), ShouldBeNil)
+			So(sat.order, ShouldResemble, []int{1, 2})
+			So(p.Overflows(), ShouldEqual, 1)
+		})
+	})
+}
+
+// saturation occupies the worker of a pool with block until release is closed,
+// so that the tasks submitted meanwhile are queued; record returns a task
+// appending n to order, to check the order the queued tasks run in.
+type saturation struct {
+	started, release chan struct{}
+
+	mu    sync.Mutex
+	order []int
+}
+
+func newSaturation() *saturation {
+	return &saturation{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+}
+
+func (s *saturation) block() {
+	close(s.started)
+	<-s.release
+}
+
+func (s *saturation) record(n int) func() {
+	return func() {
+		s.mu.Lock()
+		s.order = append(s.order, n)
+		s.mu.Unlock()
+	}
+}
+
+// waitFor polls cond until it holds or a second passes.
+func waitFor(cond func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return cond()
+}